@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+// Ownership names the relation the caller must have with the resource
+// identified by the {id} path parameter.
+type Ownership int
+
+const (
+	// AnyResource means the rule does not depend on the resource.
+	AnyResource Ownership = iota
+	// UserSelf requires {id} to be the caller's own user id.
+	UserSelf
+	// JobCreator requires the caller to have posted the job {id}.
+	JobCreator
+	// Applicant requires the caller to have made the job application {id}.
+	Applicant
+	// ApplicationJobCreator requires the caller to have posted the job that
+	// the job application {id} was made to.
+	ApplicationJobCreator
+	// JobSeriesCreator requires the caller to have posted the job series {id}.
+	JobSeriesCreator
+)
+
+// Rule is what an operation requires of its caller. A caller must hold one
+// of Roles, when any are listed, and satisfy Owner. Admins satisfy every rule.
+type Rule struct {
+	Roles []models.UserRoles
+	Owner Ownership
+}
+
+// Rules maps every protected operationId to its authorization rule.
+// Operations missing from the map are denied.
+var Rules = map[string]Rule{
+	"delete_job_application":        {Roles: []models.UserRoles{models.PetSitter}, Owner: Applicant},
+	"update_job_application":        {Roles: []models.UserRoles{models.PetOwner}, Owner: ApplicationJobCreator},
+	"post_job_series":               {Roles: []models.UserRoles{models.PetOwner}},
+	"get_job_series_id":             {},
+	"put_job_series_id":             {Roles: []models.UserRoles{models.PetOwner}, Owner: JobSeriesCreator},
+	"apply_to_job_series":           {Roles: []models.UserRoles{models.PetSitter}},
+	"get_jobs":                      {},
+	"post_jobs":                     {Roles: []models.UserRoles{models.PetOwner}},
+	"delete_jobs_id":                {Roles: []models.UserRoles{models.PetOwner}, Owner: JobCreator},
+	"get_jobs_id":                   {},
+	"put_jobs_id":                   {Roles: []models.UserRoles{models.PetOwner}, Owner: JobCreator},
+	"get_applications_by_job_id":    {Roles: []models.UserRoles{models.PetOwner}, Owner: JobCreator},
+	"create_job_application":        {Roles: []models.UserRoles{models.PetSitter}},
+	"get_job_matches":               {Roles: []models.UserRoles{models.PetOwner}, Owner: JobCreator},
+	"delete_users_id":               {Owner: UserSelf},
+	"get_users_id":                  {Owner: UserSelf},
+	"put_users_id":                  {Owner: UserSelf},
+	"get_user_availability":         {},
+	"put_user_availability":         {Roles: []models.UserRoles{models.PetSitter}, Owner: UserSelf},
+	"get_user_pets":                 {Owner: UserSelf},
+	"post_user_pet":                 {Roles: []models.UserRoles{models.PetOwner}, Owner: UserSelf},
+	"get_user_pet":                  {Owner: UserSelf},
+	"put_user_pet":                  {Roles: []models.UserRoles{models.PetOwner}, Owner: UserSelf},
+	"delete_user_pet":               {Roles: []models.UserRoles{models.PetOwner}, Owner: UserSelf},
+	"post_calendar_feed":            {Owner: UserSelf},
+	"delete_calendar_feed":          {Owner: UserSelf},
+	"get_job_applications_for_user": {Owner: UserSelf},
+	"get_jobs_for_user":             {Owner: UserSelf},
+}
+
+// OwnerFunc returns the id of the user owning the resource with the given id.
+type OwnerFunc func(ctx context.Context, id string) (string, error)
+
+type Authorizer struct {
+	rules  map[string]Rule
+	owners map[Ownership]OwnerFunc
+}
+
+func NewAuthorizer(rules map[string]Rule) *Authorizer {
+	return &Authorizer{
+		rules: rules,
+		owners: map[Ownership]OwnerFunc{
+			UserSelf: func(_ context.Context, id string) (string, error) {
+				return id, nil
+			},
+		},
+	}
+}
+
+// RegisterOwner installs the lookup used for rules with the given ownership.
+// Rules whose ownership has no lookup registered deny every non admin caller.
+func (a *Authorizer) RegisterOwner(kind Ownership, fn OwnerFunc) {
+	a.owners[kind] = fn
+}
+
+// Authorize returns nil when p may call operation on the resource id, and
+// an error wrapping domain.ErrForbidden otherwise. scopes are the scopes the
+// spec declares for the operation; each must be one of the caller's roles.
+func (a *Authorizer) Authorize(ctx context.Context, p Principal, operation string, scopes []string, id string) error {
+	if p.HasRole(models.Admin) {
+		return nil
+	}
+
+	for _, scope := range scopes {
+		if !p.HasRole(models.UserRoles(scope)) {
+			return fmt.Errorf("%s requires role %s: %w", operation, scope, domain.ErrForbidden)
+		}
+	}
+
+	rule, ok := a.rules[operation]
+	if !ok {
+		return fmt.Errorf("no rule for %s: %w", operation, domain.ErrForbidden)
+	}
+
+	if len(rule.Roles) > 0 && !hasAnyRole(p, rule.Roles) {
+		return fmt.Errorf("%s requires one of roles %v: %w", operation, rule.Roles, domain.ErrForbidden)
+	}
+
+	if rule.Owner == AnyResource {
+		return nil
+	}
+
+	lookup, ok := a.owners[rule.Owner]
+	if !ok {
+		return fmt.Errorf("no owner lookup for %s: %w", operation, domain.ErrForbidden)
+	}
+	owner, err := lookup(ctx, id)
+	if err != nil {
+		return err
+	}
+	if owner != p.UserId {
+		return fmt.Errorf("%s on %s: %w", operation, id, domain.ErrForbidden)
+	}
+
+	return nil
+}
+
+func hasAnyRole(p Principal, roles []models.UserRoles) bool {
+	for _, role := range roles {
+		if p.HasRole(role) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+func TestAuthorize(t *testing.T) {
+	owners := map[string]string{
+		"job-1":    "owner-1",
+		"app-1":    "sitter-1",
+		"series-1": "owner-1",
+	}
+	lookup := func(_ context.Context, id string) (string, error) {
+		owner, ok := owners[id]
+		if !ok {
+			return "", domain.ErrNotFound
+		}
+		return owner, nil
+	}
+
+	authorizer := NewAuthorizer(Rules)
+	authorizer.RegisterOwner(JobCreator, lookup)
+	authorizer.RegisterOwner(Applicant, lookup)
+	authorizer.RegisterOwner(ApplicationJobCreator, func(ctx context.Context, id string) (string, error) {
+		if id != "app-1" {
+			return "", domain.ErrNotFound
+		}
+		return lookup(ctx, "job-1")
+	})
+
+	owner := Principal{UserId: "owner-1", Roles: []models.UserRoles{models.PetOwner}}
+	otherOwner := Principal{UserId: "owner-2", Roles: []models.UserRoles{models.PetOwner}}
+	sitter := Principal{UserId: "sitter-1", Roles: []models.UserRoles{models.PetSitter}}
+	otherSitter := Principal{UserId: "sitter-2", Roles: []models.UserRoles{models.PetSitter}}
+	both := Principal{UserId: "owner-1", Roles: []models.UserRoles{models.PetOwner, models.PetSitter}}
+	// applicantOwner made app-1 and is a pet owner too, but did not post the job.
+	applicantOwner := Principal{UserId: "sitter-1", Roles: []models.UserRoles{models.PetOwner, models.PetSitter}}
+	admin := Principal{UserId: "admin-1", Roles: []models.UserRoles{models.Admin}}
+
+	tests := []struct {
+		name      string
+		principal Principal
+		operation string
+		scopes    []string
+		id        string
+		err       error
+	}{
+		{name: "open operation", principal: sitter, operation: "get_jobs"},
+		{name: "role required", principal: owner, operation: "post_jobs"},
+		{name: "missing role", principal: sitter, operation: "post_jobs", err: domain.ErrForbidden},
+		{name: "one of several roles", principal: both, operation: "create_job_application"},
+		{name: "unknown operation", principal: owner, operation: "drop_database", err: domain.ErrForbidden},
+		{name: "spec scope held", principal: owner, operation: "get_jobs", scopes: []string{"PetOwner"}},
+		{name: "spec scope missing", principal: sitter, operation: "get_jobs", scopes: []string{"PetOwner"}, err: domain.ErrForbidden},
+		{name: "own user", principal: sitter, operation: "get_users_id", id: "sitter-1"},
+		{name: "other user", principal: sitter, operation: "get_users_id", id: "sitter-2", err: domain.ErrForbidden},
+		{name: "own user with wrong role", principal: sitter, operation: "post_user_pet", id: "sitter-1", err: domain.ErrForbidden},
+		{name: "job creator", principal: owner, operation: "put_jobs_id", id: "job-1"},
+		{name: "other job creator", principal: otherOwner, operation: "put_jobs_id", id: "job-1", err: domain.ErrForbidden},
+		{name: "missing job", principal: owner, operation: "delete_jobs_id", id: "job-2", err: domain.ErrNotFound},
+		{name: "applicant", principal: sitter, operation: "delete_job_application", id: "app-1"},
+		{name: "other applicant", principal: otherSitter, operation: "delete_job_application", id: "app-1", err: domain.ErrForbidden},
+		{name: "creator of the applied job", principal: owner, operation: "update_job_application", id: "app-1"},
+		{name: "job creator cannot withdraw the application", principal: both, operation: "delete_job_application", id: "app-1", err: domain.ErrForbidden},
+		{name: "other owner cannot accept", principal: otherOwner, operation: "update_job_application", id: "app-1", err: domain.ErrForbidden},
+		{name: "applicant cannot accept", principal: applicantOwner, operation: "update_job_application", id: "app-1", err: domain.ErrForbidden},
+		{name: "no owner lookup registered", principal: owner, operation: "put_job_series_id", id: "series-1", err: domain.ErrForbidden},
+		{name: "admin bypasses roles", principal: admin, operation: "post_jobs"},
+		{name: "admin bypasses ownership", principal: admin, operation: "put_jobs_id", id: "job-2"},
+		{name: "admin bypasses scopes", principal: admin, operation: "get_jobs", scopes: []string{"PetOwner"}},
+		{name: "admin bypasses unknown operations", principal: admin, operation: "drop_database"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(context.Background(), tt.principal, tt.operation, tt.scopes, tt.id)
+			if tt.err == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestUserSelfOwner(t *testing.T) {
+	authorizer := NewAuthorizer(Rules)
+
+	owner, err := authorizer.owners[UserSelf](context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owner != "user-1" {
+		t.Fatalf("expected user-1 to own itself, got %s", owner)
+	}
+}
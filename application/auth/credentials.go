@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/logging"
+	"golang.org/x/crypto/argon2"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrMalformedHash      = errors.New("malformed password hash")
+)
+
+// PasswordParams are the argon2id cost parameters used for new hashes.
+type PasswordParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// PasswordPolicy describes what a user supplied password must look like.
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// PolicyError lists every rule a password failed.
+type PolicyError struct {
+	Violations []string
+}
+
+func (e *PolicyError) Error() string {
+	return "password " + strings.Join(e.Violations, ", ")
+}
+
+type Credentials struct {
+	params PasswordParams
+	policy PasswordPolicy
+	users  domain.UserRepository
+	// dummyHash is verified against for unknown emails, so that they take
+	// as long to reject as wrong passwords.
+	dummyHash string
+}
+
+// NewCredentials hashes new passwords with params and checks them against
+// policy.
+func NewCredentials(params PasswordParams, policy PasswordPolicy, users domain.UserRepository) *Credentials {
+	salt := make([]byte, params.SaltLength)
+	key := argon2.IDKey([]byte("dummy password"), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return &Credentials{
+		params:    params,
+		policy:    policy,
+		users:     users,
+		dummyHash: encodeHash(params, salt, key),
+	}
+}
+
+// CheckPolicy returns a *PolicyError when password does not satisfy the
+// configured password policy.
+func (c *Credentials) CheckPolicy(password string) error {
+	var violations []string
+
+	length := len([]rune(password))
+	if length < c.policy.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters", c.policy.MinLength))
+	}
+	if c.policy.MaxLength > 0 && length > c.policy.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters", c.policy.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if c.policy.RequireUpper && !upper {
+		violations = append(violations, "must contain an upper case letter")
+	}
+	if c.policy.RequireLower && !lower {
+		violations = append(violations, "must contain a lower case letter")
+	}
+	if c.policy.RequireDigit && !digit {
+		violations = append(violations, "must contain a digit")
+	}
+	if c.policy.RequireSymbol && !symbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// Hash returns password hashed with argon2id in the PHC string format
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>.
+func (c *Credentials) Hash(password string) (string, error) {
+	salt := make([]byte, c.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generating salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, c.params.Iterations, c.params.Memory, c.params.Parallelism, c.params.KeyLength)
+
+	return encodeHash(c.params, salt, key), nil
+}
+
+func encodeHash(params PasswordParams, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+// Verify reports whether password matches encoded, and whether encoded was
+// produced with parameters that differ from the configured ones.
+func (c *Credentials) Verify(password, encoded string) (match bool, needsRehash bool, err error) {
+	params, salt, key, err := decodeHash(encoded)
+	if err != nil {
+		return false, false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, false, nil
+	}
+
+	needsRehash = params.Memory != c.params.Memory ||
+		params.Iterations != c.params.Iterations ||
+		params.Parallelism != c.params.Parallelism ||
+		uint32(len(salt)) != c.params.SaltLength ||
+		uint32(len(key)) != c.params.KeyLength
+
+	return true, needsRehash, nil
+}
+
+// Authenticate checks email and password against the stored hash and returns
+// the matching user. Unknown emails cost as much as wrong passwords, so that
+// the time taken does not tell which accounts exist. A hash produced with
+// outdated parameters is replaced.
+func (c *Credentials) Authenticate(ctx context.Context, email, password string) (models.User, error) {
+	user, hash, err := c.users.GetUserCredentials(ctx, email)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.Verify(password, c.dummyHash)
+		return models.User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	match, needsRehash, err := c.Verify(password, hash)
+	if err != nil {
+		return models.User{}, err
+	}
+	if !match {
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	// The password is correct; failing to upgrade its hash is no reason to
+	// turn the user away, the next login tries again.
+	if needsRehash {
+		if err := c.rehash(ctx, *user.Id, password); err != nil {
+			logging.FromContext(ctx).Warn("Rehashing password", slog.String("user_id", *user.Id), slog.Any("error", err))
+		}
+	}
+
+	return user, nil
+}
+
+func (c *Credentials) rehash(ctx context.Context, userId, password string) error {
+	rehashed, err := c.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return c.users.UpdatePasswordHash(ctx, userId, rehashed)
+}
+
+func decodeHash(encoded string) (PasswordParams, []byte, []byte, error) {
+	var params PasswordParams
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
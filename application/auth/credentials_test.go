@@ -0,0 +1,300 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/infrastructure/repositories/memory"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// testParams keeps argon2 cheap enough to hash in every test case.
+var testParams = PasswordParams{
+	Memory:      8 * 1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestCheckPolicy(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:     8,
+		MaxLength:     16,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		name       string
+		policy     PasswordPolicy
+		password   string
+		violations []string
+	}{
+		{
+			name:     "satisfies every rule",
+			policy:   policy,
+			password: "Correct1horse!",
+		},
+		{
+			name:       "too short",
+			policy:     policy,
+			password:   "Ab1!",
+			violations: []string{"must be at least 8 characters"},
+		},
+		{
+			name:       "too long",
+			policy:     policy,
+			password:   "Correct1horse!battery",
+			violations: []string{"must be at most 16 characters"},
+		},
+		{
+			name:     "length counts runes not bytes",
+			policy:   PasswordPolicy{MinLength: 4, MaxLength: 4},
+			password: "äöüß",
+		},
+		{
+			name:     "no maximum when zero",
+			policy:   PasswordPolicy{MinLength: 1},
+			password: strings.Repeat("a", 1000),
+		},
+		{
+			name:     "lists every failed rule",
+			policy:   policy,
+			password: "abc",
+			violations: []string{
+				"must be at least 8 characters",
+				"must contain an upper case letter",
+				"must contain a digit",
+				"must contain a symbol",
+			},
+		},
+		{
+			name:       "unicode symbols count",
+			policy:     PasswordPolicy{RequireSymbol: true},
+			password:   "price€",
+			violations: nil,
+		},
+		{
+			name:       "missing lower case",
+			policy:     PasswordPolicy{RequireLower: true},
+			password:   "SHOUTING1!",
+			violations: []string{"must contain a lower case letter"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCredentials(testParams, tt.policy, nil)
+
+			err := c.CheckPolicy(tt.password)
+			if tt.violations == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var policyErr *PolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("expected a *PolicyError, got %v", err)
+			}
+			if !reflect.DeepEqual(policyErr.Violations, tt.violations) {
+				t.Fatalf("expected violations %q, got %q", tt.violations, policyErr.Violations)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	c := NewCredentials(testParams, PasswordPolicy{}, nil)
+
+	hash, err := c.Hash("s3cret!")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=8192,t=1,p=1$") {
+		t.Fatalf("unexpected hash format %s", hash)
+	}
+
+	other, err := c.Hash("s3cret!")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other == hash {
+		t.Fatal("expected every hash to use a fresh salt")
+	}
+
+	withParams := func(params PasswordParams) string {
+		hash, err := NewCredentials(params, PasswordPolicy{}, nil).Hash("s3cret!")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return hash
+	}
+
+	cheaper := testParams
+	cheaper.Memory = 4 * 1024
+	longerSalt := testParams
+	longerSalt.SaltLength = 32
+	shorterKey := testParams
+	shorterKey.KeyLength = 16
+	moreIterations := testParams
+	moreIterations.Iterations = 2
+
+	tests := []struct {
+		name        string
+		password    string
+		encoded     string
+		match       bool
+		needsRehash bool
+		err         error
+	}{
+		{name: "matching password", password: "s3cret!", encoded: hash, match: true},
+		{name: "wrong password", password: "s3cret?", encoded: hash},
+		{name: "empty password", password: "", encoded: hash},
+		{name: "older memory cost", password: "s3cret!", encoded: withParams(cheaper), match: true, needsRehash: true},
+		{name: "older iteration count", password: "s3cret!", encoded: withParams(moreIterations), match: true, needsRehash: true},
+		{name: "different salt length", password: "s3cret!", encoded: withParams(longerSalt), match: true, needsRehash: true},
+		{name: "different key length", password: "s3cret!", encoded: withParams(shorterKey), match: true, needsRehash: true},
+		{name: "wrong password never asks for a rehash", password: "nope", encoded: withParams(cheaper)},
+		{name: "bcrypt hash", password: "s3cret!", encoded: "$2a$10$abcdefghijklmnopqrstuv", err: ErrMalformedHash},
+		{name: "argon2i hash", password: "s3cret!", encoded: strings.Replace(hash, "argon2id", "argon2i", 1), err: ErrMalformedHash},
+		{name: "unknown version", password: "s3cret!", encoded: strings.Replace(hash, "v=19", "v=16", 1), err: ErrMalformedHash},
+		{name: "bad parameters", password: "s3cret!", encoded: strings.Replace(hash, "m=8192", "m=lots", 1), err: ErrMalformedHash},
+		{name: "bad salt encoding", password: "s3cret!", encoded: "$argon2id$v=19$m=8192,t=1,p=1$!!!$AAAA", err: ErrMalformedHash},
+		{name: "empty", password: "s3cret!", encoded: "", err: ErrMalformedHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, needsRehash, err := c.Verify(tt.password, tt.encoded)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if match != tt.match {
+				t.Fatalf("expected match %t, got %t", tt.match, match)
+			}
+			if needsRehash != tt.needsRehash {
+				t.Fatalf("expected needsRehash %t, got %t", tt.needsRehash, needsRehash)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRehashes(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+
+	cheaper := testParams
+	cheaper.Memory = 4 * 1024
+	oldHash, err := NewCredentials(cheaper, PasswordPolicy{}, users).Hash("s3cret!")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = users.PostUsers(ctx, models.User{
+		Email:    openapi_types.Email("jane@example.com"),
+		FullName: "Jane Doe",
+		Roles:    []models.UserRoles{models.PetOwner},
+	}, oldHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c := NewCredentials(testParams, PasswordPolicy{}, users)
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		err      error
+	}{
+		{name: "unknown email", email: "john@example.com", password: "s3cret!", err: ErrInvalidCredentials},
+		{name: "wrong password", email: "jane@example.com", password: "wrong", err: ErrInvalidCredentials},
+		{name: "correct password", email: "jane@example.com", password: "s3cret!"},
+		{name: "correct password after rehash", email: "jane@example.com", password: "s3cret!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.Authenticate(ctx, tt.email, tt.password)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	_, hash, err := users.GetUserCredentials(ctx, "jane@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash == oldHash {
+		t.Fatal("expected the outdated hash to be replaced")
+	}
+	if _, needsRehash, _ := c.Verify("s3cret!", hash); needsRehash {
+		t.Fatal("expected the stored hash to use the current parameters")
+	}
+}
+
+// failingUsers cannot store password hashes.
+type failingUsers struct {
+	*memory.UserRepository
+}
+
+func (failingUsers) UpdatePasswordHash(ctx context.Context, id string, hash string) error {
+	return errors.New("database unavailable")
+}
+
+func TestAuthenticateSurvivesFailedRehash(t *testing.T) {
+	ctx := context.Background()
+	users := failingUsers{memory.NewUserRepository()}
+
+	cheaper := testParams
+	cheaper.Memory = 4 * 1024
+	oldHash, err := NewCredentials(cheaper, PasswordPolicy{}, users).Hash("s3cret!")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created, err := users.PostUsers(ctx, models.User{Email: "jane@example.com", FullName: "Jane Doe"}, oldHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	user, err := NewCredentials(testParams, PasswordPolicy{}, users).Authenticate(ctx, "jane@example.com", "s3cret!")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *user.Id != *created.Id {
+		t.Fatalf("expected user %s, got %s", *created.Id, *user.Id)
+	}
+}
+
+func TestDummyHashCostsAsMuchAsARealOne(t *testing.T) {
+	c := NewCredentials(testParams, PasswordPolicy{}, memory.NewUserRepository())
+	hash, err := c.Hash("s3cret!")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dummyParams, dummySalt, dummyKey, err := decodeHash(c.dummyHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	params, salt, key, err := decodeHash(hash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dummyParams != params || len(dummySalt) != len(salt) || len(dummyKey) != len(key) {
+		t.Fatalf("expected the dummy hash to use %+v, got %+v", params, dummyParams)
+	}
+	if match, _, _ := c.Verify("dummy password", c.dummyHash); !match {
+		t.Fatal("expected the dummy hash to be verifiable")
+	}
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+type principalKey struct{}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserId    string
+	SessionId string
+	Roles     []models.UserRoles
+}
+
+func (p Principal) HasRole(role models.UserRoles) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
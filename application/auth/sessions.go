@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidToken = errors.New("invalid session token")
+
+const tokenIssuer = "agentco"
+
+// sessionClaims carries no roles: they are read from the user on every
+// request, so that a token cannot grant itself roles and role changes apply
+// at once.
+type sessionClaims struct {
+	jwt.RegisteredClaims
+}
+
+type Sessions struct {
+	secret []byte
+	ttl    time.Duration
+	store  domain.SessionRepository
+	users  domain.UserRepository
+}
+
+// NewSessions signs session tokens with secret. Sessions last for ttl. The
+// roles of a session's caller are looked up in users.
+func NewSessions(secret string, ttl time.Duration, store domain.SessionRepository, users domain.UserRepository) (*Sessions, error) {
+	if secret == "" {
+		return nil, errors.New("a token secret is required")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("the session TTL must be positive")
+	}
+
+	return &Sessions{
+		secret: []byte(secret),
+		ttl:    ttl,
+		store:  store,
+		users:  users,
+	}, nil
+}
+
+// Start records a new session for user and returns it with a signed token
+// ready to be sent back in the Authorization header.
+func (s *Sessions) Start(ctx context.Context, user models.User) (models.Session, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	session := domain.Session{
+		Id:        primitive.NewObjectID().Hex(),
+		UserId:    *user.Id,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if err := s.store.CreateSession(ctx, session); err != nil {
+		return models.Session{}, err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, sessionClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        session.Id,
+			Subject:   session.UserId,
+			Issuer:    tokenIssuer,
+			IssuedAt:  jwt.NewNumericDate(session.CreatedAt),
+			ExpiresAt: jwt.NewNumericDate(session.ExpiresAt),
+		},
+	})
+	signed, err := token.SignedString(s.secret)
+	if err != nil {
+		return models.Session{}, fmt.Errorf("signing session token: %w", err)
+	}
+
+	authHeader := "Bearer " + signed
+
+	return models.Session{
+		UserId:     &session.UserId,
+		AuthHeader: &authHeader,
+	}, nil
+}
+
+// Validate checks the signature and expiry of the token carried in an
+// Authorization header value and that its session and user still exist.
+// The principal has the roles the user has now.
+func (s *Sessions) Validate(ctx context.Context, header string) (Principal, error) {
+	raw := strings.TrimSpace(header)
+	if len(raw) > 7 && strings.EqualFold(raw[:7], "bearer ") {
+		raw = strings.TrimSpace(raw[7:])
+	}
+	if raw == "" {
+		return Principal{}, ErrInvalidToken
+	}
+
+	var claims sessionClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(*jwt.Token) (any, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return Principal{}, ErrInvalidToken
+	}
+
+	session, err := s.store.GetSession(ctx, claims.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return Principal{}, ErrInvalidToken
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+	if session.UserId != claims.Subject || time.Now().After(session.ExpiresAt) {
+		return Principal{}, ErrInvalidToken
+	}
+
+	user, err := s.users.GetUsersId(ctx, claims.Subject)
+	if errors.Is(err, domain.ErrNotFound) {
+		return Principal{}, ErrInvalidToken
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+
+	return Principal{
+		UserId:    claims.Subject,
+		SessionId: claims.ID,
+		Roles:     user.Roles,
+	}, nil
+}
+
+// EndAll revokes every session of the user.
+func (s *Sessions) EndAll(ctx context.Context, userId string) error {
+	return s.store.DeleteUserSessions(ctx, userId)
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/infrastructure/repositories/memory"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "a-test-secret-that-is-long-enough"
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims jwt.RegisteredClaims) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(method, sessionClaims{RegisteredClaims: claims}).SignedString(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return "Bearer " + signed
+}
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewSessionRepository()
+	users := memory.NewUserRepository()
+	sessions, err := NewSessions(testSecret, time.Hour, store, users)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	user, err := users.PostUsers(ctx, models.User{Email: "sitter@example.com", FullName: "Sam Sitter", Roles: []models.UserRoles{models.PetSitter}}, "hash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	started, err := sessions.Start(ctx, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loggedOut, err := users.PostUsers(ctx, models.User{Email: "gone@example.com", FullName: "Gina Gone", Roles: []models.UserRoles{models.PetSitter}}, "hash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	revoked, err := sessions.Start(ctx, loggedOut)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sessions.EndAll(ctx, *loggedOut.Id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	principal, err := sessions.Validate(ctx, *started.AuthHeader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	claims := jwt.RegisteredClaims{
+		ID:        principal.SessionId,
+		Subject:   *user.Id,
+		Issuer:    tokenIssuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+	with := func(change func(claims *jwt.RegisteredClaims)) jwt.RegisteredClaims {
+		c := claims
+		change(&c)
+		return c
+	}
+
+	stale := domain.Session{Id: "stale", UserId: *user.Id, CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+	if err := store.CreateSession(ctx, stale); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		err    error
+	}{
+		{name: "started", header: *started.AuthHeader},
+		{name: "resigned", header: sign(t, jwt.SigningMethodHS256, []byte(testSecret), claims)},
+		{name: "lower case scheme", header: "bearer " + (*started.AuthHeader)[len("Bearer "):]},
+		{name: "empty", header: "", err: ErrInvalidToken},
+		{name: "scheme only", header: "Bearer ", err: ErrInvalidToken},
+		{name: "garbage", header: "Bearer not.a.token", err: ErrInvalidToken},
+		{name: "revoked", header: *revoked.AuthHeader, err: ErrInvalidToken},
+		{
+			name:   "expired",
+			header: sign(t, jwt.SigningMethodHS256, []byte(testSecret), with(func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) })),
+			err:    ErrInvalidToken,
+		},
+		{
+			name:   "without expiry",
+			header: sign(t, jwt.SigningMethodHS256, []byte(testSecret), with(func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil })),
+			err:    ErrInvalidToken,
+		},
+		{
+			name: "session expired in the store",
+			header: sign(t, jwt.SigningMethodHS256, []byte(testSecret), with(func(c *jwt.RegisteredClaims) {
+				c.ID = stale.Id
+			})),
+			err: ErrInvalidToken,
+		},
+		{
+			name:   "forged signature",
+			header: sign(t, jwt.SigningMethodHS256, []byte("another-secret-that-is-long-enough"), claims),
+			err:    ErrInvalidToken,
+		},
+		{
+			name:   "unsigned",
+			header: sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims),
+			err:    ErrInvalidToken,
+		},
+		{
+			name:   "another issuer",
+			header: sign(t, jwt.SigningMethodHS256, []byte(testSecret), with(func(c *jwt.RegisteredClaims) { c.Issuer = "elsewhere" })),
+			err:    ErrInvalidToken,
+		},
+		{
+			name:   "subject swapped",
+			header: sign(t, jwt.SigningMethodHS256, []byte(testSecret), with(func(c *jwt.RegisteredClaims) { c.Subject = "someone-else" })),
+			err:    ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sessions.Validate(ctx, tt.header)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.UserId != *user.Id || got.SessionId != principal.SessionId {
+				t.Fatalf("unexpected principal %+v", got)
+			}
+		})
+	}
+}
+
+func TestValidateReadsCurrentRoles(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions, err := NewSessions(testSecret, time.Hour, memory.NewSessionRepository(), users)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	user, err := users.PostUsers(ctx, models.User{Email: "owner@example.com", FullName: "Olga Owner", Roles: []models.UserRoles{models.PetOwner}}, "hash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	session, err := sessions.Start(ctx, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		roles []models.UserRoles
+	}{
+		{name: "as issued", roles: []models.UserRoles{models.PetOwner}},
+		{name: "role added", roles: []models.UserRoles{models.PetOwner, models.PetSitter}},
+		{name: "role removed", roles: []models.UserRoles{models.PetSitter}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user.Roles = tt.roles
+			if _, err := users.PutUsersId(ctx, *user.Id, user, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			principal, err := sessions.Validate(ctx, *session.AuthHeader)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(principal.Roles, tt.roles) {
+				t.Fatalf("expected roles %v, got %v", tt.roles, principal.Roles)
+			}
+		})
+	}
+
+	if err := users.DeleteUsersId(ctx, *user.Id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := sessions.Validate(ctx, *session.AuthHeader); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v once the user is deleted, got %v", ErrInvalidToken, err)
+	}
+}
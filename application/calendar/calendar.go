@@ -0,0 +1,297 @@
+// Package calendar publishes a user's jobs as an iCalendar feed that
+// calendar clients subscribe to. Clients cannot sign in, so each feed is
+// opened by a secret token carried in its URL.
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+// History is how long after they started jobs stay in a feed.
+const History = 90 * 24 * time.Hour
+
+const (
+	productId = "-//AgentCo//Jobs//EN"
+	uidDomain = "agentco"
+	// refreshInterval is how often clients are asked to fetch the feed.
+	refreshInterval = "PT1H"
+	// publishAttempts bounds how often Publish renders a feed that other
+	// fetches keep publishing first.
+	publishAttempts = 3
+)
+
+var activityLabels = map[models.JobActivities]string{
+	models.Walk:     "Walk",
+	models.Dropin:   "Drop-in",
+	models.Boarding: "Boarding",
+	models.Sitting:  "Sitting",
+	models.Daycare:  "Daycare",
+}
+
+type Service struct {
+	feeds domain.CalendarFeedRepository
+}
+
+func New(feeds domain.CalendarFeedRepository) *Service {
+	return &Service{
+		feeds: feeds,
+	}
+}
+
+// Issue returns a new token for the user's feed, creating the feed when
+// needed. Earlier tokens stop working.
+func (s *Service) Issue(ctx context.Context, userId string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating calendar token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := s.feeds.PutCalendarToken(ctx, userId, hashToken(token)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Revoke deletes the user's feed, so that no token opens it.
+func (s *Service) Revoke(ctx context.Context, userId string) error {
+	return s.feeds.DeleteCalendarFeed(ctx, userId)
+}
+
+// Open returns the feed of userId when token opens it, and an error wrapping
+// domain.ErrNotFound otherwise.
+func (s *Service) Open(ctx context.Context, userId string, token string) (domain.CalendarFeed, error) {
+	feed, err := s.feeds.GetCalendarFeedByToken(ctx, hashToken(token))
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+	if feed.UserId != userId {
+		return domain.CalendarFeed{}, fmt.Errorf("calendar feed of user %s: %w", userId, domain.ErrNotFound)
+	}
+
+	return feed, nil
+}
+
+// Publish renders jobs as the iCalendar feed and records what it published.
+// A job whose event changed since the last fetch gets a higher SEQUENCE,
+// and a job that left the feed is published as cancelled until it would
+// have ended. link returns the URL of a job.
+//
+// Fetches of the same feed can run at once. When another one records its
+// events first, the feed is read again and the jobs rendered against it, so
+// that every SEQUENCE is only ever raised once per change.
+func (s *Service) Publish(ctx context.Context, feed domain.CalendarFeed, jobs []models.Job, link func(jobId string) string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, events := render(feed, jobs, link, time.Now())
+		if sameEvents(feed.Events, events) {
+			return body, nil
+		}
+
+		err := s.feeds.PutCalendarEvents(ctx, feed.UserId, feed.Version, events)
+		if err == nil {
+			return body, nil
+		}
+		if !errors.Is(err, domain.ErrConflict) || attempt == publishAttempts {
+			return nil, err
+		}
+
+		if feed, err = s.feeds.GetCalendarFeedByToken(ctx, feed.TokenHash); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// render returns jobs as the iCalendar feed following on from the events
+// feed last published, together with the events it publishes.
+func render(feed domain.CalendarFeed, jobs []models.Job, link func(jobId string) string, now time.Time) ([]byte, []domain.CalendarEvent) {
+	previous := make(map[string]domain.CalendarEvent, len(feed.Events))
+	for _, event := range feed.Events {
+		previous[event.JobId] = event
+	}
+
+	var w writer
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", productId)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.text("X-WR-CALNAME", "AgentCo jobs")
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", refreshInterval)
+	w.line("X-PUBLISHED-TTL", refreshInterval)
+
+	events := make([]domain.CalendarEvent, 0, len(jobs))
+	for _, job := range jobs {
+		e := newEvent(job, link(*job.Id))
+		published := domain.CalendarEvent{
+			JobId:       *job.Id,
+			Fingerprint: e.fingerprint(),
+			Summary:     e.summary,
+			StartsAt:    job.StartsAt,
+			EndsAt:      job.EndsAt,
+		}
+		if last, ok := previous[*job.Id]; ok {
+			published.Sequence = last.Sequence
+			if last.Fingerprint != published.Fingerprint || last.Cancelled {
+				published.Sequence++
+			}
+			delete(previous, *job.Id)
+		}
+
+		e.write(&w, published.Sequence, now)
+		events = append(events, published)
+	}
+
+	for _, last := range feed.Events {
+		if _, gone := previous[last.JobId]; !gone || !last.EndsAt.After(now) {
+			continue
+		}
+		if !last.Cancelled {
+			last.Cancelled = true
+			last.Sequence++
+		}
+
+		writeCancelled(&w, last, now)
+		events = append(events, last)
+	}
+
+	w.line("END", "VCALENDAR")
+
+	return w.bytes(), events
+}
+
+// event is the content of the VEVENT published for a job.
+type event struct {
+	uid          string
+	summary      string
+	description  string
+	url          string
+	status       string
+	categories   []string
+	startsAt     time.Time
+	endsAt       time.Time
+	lastModified *time.Time
+}
+
+func newEvent(job models.Job, link string) event {
+	var labels []string
+	for _, activity := range job.Activities {
+		labels = append(labels, activityLabels[activity])
+	}
+
+	summary := strings.Join(labels, ", ")
+	var names, details []string
+	for _, dog := range domain.JobDogs(job) {
+		name := dog.Breed
+		if dog.Name != nil && *dog.Name != "" {
+			name = *dog.Name
+		}
+		names = append(names, name)
+
+		dogDetails := fmt.Sprintf("%s, %s, %d years old", dog.Breed, dog.Size, dog.YearsOld)
+		if dog.Name != nil && *dog.Name != "" {
+			dogDetails = *dog.Name + ", " + dogDetails
+		}
+		details = append(details, "Dog: "+dogDetails)
+	}
+	if len(names) > 0 {
+		summary += ": " + strings.Join(names, ", ")
+	}
+	details = append(details, "Activities: "+strings.Join(labels, ", "))
+
+	status := "TENTATIVE"
+	if job.WorkerUserId != nil {
+		status = "CONFIRMED"
+	}
+
+	return event{
+		uid:          uid(*job.Id),
+		summary:      summary,
+		description:  job.Description + "\n\n" + strings.Join(details, "\n") + "\n\n" + link,
+		url:          link,
+		status:       status,
+		categories:   labels,
+		startsAt:     job.StartsAt,
+		endsAt:       job.EndsAt,
+		lastModified: job.UpdatedAt,
+	}
+}
+
+// fingerprint identifies what a calendar client shows of e.
+func (e event) fingerprint() string {
+	h := sha256.New()
+	for _, field := range []string{
+		e.summary, e.description, e.url, e.status, strings.Join(e.categories, ","),
+		e.startsAt.UTC().Format(time.RFC3339), e.endsAt.UTC().Format(time.RFC3339),
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func (e event) write(w *writer, sequence int, now time.Time) {
+	w.line("BEGIN", "VEVENT")
+	w.text("UID", e.uid)
+	w.time("DTSTAMP", now)
+	w.time("DTSTART", e.startsAt)
+	w.time("DTEND", e.endsAt)
+	if e.lastModified != nil {
+		w.time("LAST-MODIFIED", *e.lastModified)
+	}
+	w.line("SEQUENCE", fmt.Sprint(sequence))
+	w.line("STATUS", e.status)
+	w.text("SUMMARY", e.summary)
+	w.text("DESCRIPTION", e.description)
+	w.list("CATEGORIES", e.categories)
+	w.line("URL", e.url)
+	w.line("END", "VEVENT")
+}
+
+func writeCancelled(w *writer, last domain.CalendarEvent, now time.Time) {
+	w.line("BEGIN", "VEVENT")
+	w.text("UID", uid(last.JobId))
+	w.time("DTSTAMP", now)
+	w.time("DTSTART", last.StartsAt)
+	w.time("DTEND", last.EndsAt)
+	w.line("SEQUENCE", fmt.Sprint(last.Sequence))
+	w.line("STATUS", "CANCELLED")
+	w.text("SUMMARY", last.Summary)
+	w.line("END", "VEVENT")
+}
+
+func uid(jobId string) string {
+	return jobId + "@" + uidDomain
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func sameEvents(a, b []domain.CalendarEvent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].JobId != b[i].JobId || a[i].Sequence != b[i].Sequence || a[i].Fingerprint != b[i].Fingerprint ||
+			a[i].Cancelled != b[i].Cancelled || a[i].Summary != b[i].Summary ||
+			!a[i].StartsAt.Equal(b[i].StartsAt) || !a[i].EndsAt.Equal(b[i].EndsAt) {
+			return false
+		}
+	}
+
+	return true
+}
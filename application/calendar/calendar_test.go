@@ -0,0 +1,175 @@
+package calendar
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/infrastructure/repositories/memory"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func link(jobId string) string {
+	return "https://agentco.example.com/jobs/" + jobId
+}
+
+var sequenceLine = regexp.MustCompile(`(?m)^SEQUENCE:(\d+)\r$`)
+
+func newJob(id string, startsAt time.Time) models.Job {
+	return models.Job{
+		Id:          ptr(id),
+		Description: "Walk Rex around the park",
+		Dog:         &models.JobDog{Name: ptr("Rex"), Breed: "Beagle", Size: models.Small, YearsOld: 3},
+		Activities:  []models.JobActivities{models.Walk},
+		StartsAt:    startsAt,
+		EndsAt:      startsAt.Add(time.Hour),
+	}
+}
+
+func TestRenderSequence(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	job := newJob("job-1", now.Add(24*time.Hour))
+	changed := job
+	changed.Description = "Walk Rex around the lake"
+	touched := job
+	touched.UpdatedAt = ptr(now)
+	filled := job
+	filled.WorkerUserId = ptr("sitter-1")
+	past := newJob("job-1", now.Add(-2*time.Hour))
+
+	// Every step renders against the events the previous one published.
+	tests := []struct {
+		name      string
+		jobs      []models.Job
+		now       time.Time
+		sequence  []string
+		cancelled bool
+	}{
+		{name: "first published", jobs: []models.Job{job}, now: now, sequence: []string{"0"}},
+		{name: "unchanged", jobs: []models.Job{job}, now: now, sequence: []string{"0"}},
+		{name: "only the update time changed", jobs: []models.Job{touched}, now: now, sequence: []string{"0"}},
+		{name: "description changed", jobs: []models.Job{changed}, now: now, sequence: []string{"1"}},
+		{name: "worker accepted", jobs: []models.Job{filled}, now: now, sequence: []string{"2"}},
+		{name: "left the feed", now: now, sequence: []string{"3"}, cancelled: true},
+		{name: "still gone", now: now, sequence: []string{"3"}, cancelled: true},
+		{name: "back in the feed", jobs: []models.Job{filled}, now: now, sequence: []string{"4"}},
+		{name: "another job alongside", jobs: []models.Job{filled, newJob("job-2", now)}, now: now, sequence: []string{"4", "0"}},
+		{name: "cancelled only until it would have ended", jobs: []models.Job{newJob("job-2", now)}, now: now.Add(48 * time.Hour), sequence: []string{"0"}},
+		{name: "jobs that started stay", jobs: []models.Job{newJob("job-2", now), past}, now: now, sequence: []string{"0", "0"}},
+	}
+
+	var feed domain.CalendarFeed
+	for _, tt := range tests {
+		body, events := render(feed, tt.jobs, link, tt.now)
+		feed.Events = events
+
+		var sequence []string
+		for _, match := range sequenceLine.FindAllStringSubmatch(string(body), -1) {
+			sequence = append(sequence, match[1])
+		}
+		if strings.Join(sequence, ",") != strings.Join(tt.sequence, ",") {
+			t.Fatalf("%s: expected SEQUENCE %v, got %v", tt.name, tt.sequence, sequence)
+		}
+		if strings.Contains(string(body), "STATUS:CANCELLED\r\n") != tt.cancelled {
+			t.Fatalf("%s: expected cancelled %t in\n%s", tt.name, tt.cancelled, body)
+		}
+		if len(events) != len(tt.sequence) {
+			t.Fatalf("%s: expected %d events, got %+v", tt.name, len(tt.sequence), events)
+		}
+	}
+}
+
+func TestRenderEvent(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	job := newJob("job-1", now)
+	job.Description = "Bring treats; Rex pulls, a lot"
+	job.Activities = []models.JobActivities{models.Walk, models.Dropin}
+	job.WorkerUserId = ptr("sitter-1")
+
+	body, _ := render(domain.CalendarFeed{}, []models.Job{job}, link, now)
+	unfolded := strings.ReplaceAll(string(body), "\r\n ", "")
+
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:job-1@agentco\r\n",
+		"DTSTAMP:20240304T120000Z\r\n",
+		"DTSTART:20240304T120000Z\r\n",
+		"DTEND:20240304T130000Z\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"SUMMARY:Walk\\, Drop-in: Rex\r\n",
+		"DESCRIPTION:Bring treats\\; Rex pulls\\, a lot\\n\\nDog: Rex\\, Beagle\\, small\\, 3 years old\\nActivities: Walk\\, Drop-in\\n\\nhttps://agentco.example.com/jobs/job-1\r\n",
+		"CATEGORIES:Walk,Drop-in\r\n",
+		"URL:https://agentco.example.com/jobs/job-1\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, expected) {
+			t.Fatalf("expected %q in\n%s", expected, unfolded)
+		}
+	}
+}
+
+func TestPublishRaisesSequenceOnce(t *testing.T) {
+	ctx := context.Background()
+	feeds := memory.NewCalendarFeedRepository()
+	service := New(feeds)
+
+	token, err := service.Issue(ctx, "owner-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	job := newJob("job-1", time.Now().Add(24*time.Hour))
+
+	feed, err := service.Open(ctx, "owner-1", token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.Publish(ctx, feed, []models.Job{job}, link); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Two fetches read the feed before either publishes the changed job.
+	first, err := service.Open(ctx, "owner-1", token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second := first
+	job.Description = "Walk Rex around the lake"
+
+	tests := []struct {
+		name string
+		feed domain.CalendarFeed
+	}{
+		{name: "first fetch", feed: first},
+		{name: "second fetch, publishing against a stale feed", feed: second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := service.Publish(ctx, tt.feed, []models.Job{job}, link)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(string(body), "SEQUENCE:1\r\n") {
+				t.Fatalf("expected SEQUENCE:1 in\n%s", body)
+			}
+		})
+	}
+
+	stored, err := service.Open(ctx, "owner-1", token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored.Events) != 1 || stored.Events[0].Sequence != 1 {
+		t.Fatalf("expected one event at sequence 1, got %+v", stored.Events)
+	}
+	if stored.Version != 2 {
+		t.Fatalf("expected the events to have been replaced twice, got version %d", stored.Version)
+	}
+}
@@ -0,0 +1,63 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line RFC 5545 allows before it has
+// to be folded.
+const maxLineOctets = 75
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+// writer writes iCalendar content lines, escaped, folded and ended with
+// CRLF as RFC 5545 requires.
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a property whose value is already in iCalendar form.
+func (w *writer) line(name, value string) {
+	l := name + ":" + value
+	limit := maxLineOctets
+	for len(l) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(l[cut]) {
+			cut--
+		}
+		w.buf.WriteString(l[:cut])
+		w.buf.WriteString("\r\n ")
+		l = l[cut:]
+		// The leading space of a continuation line counts towards its
+		// length.
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(l)
+	w.buf.WriteString("\r\n")
+}
+
+// text writes a TEXT property.
+func (w *writer) text(name, value string) {
+	w.line(name, textEscaper.Replace(value))
+}
+
+// list writes a property holding a list of TEXT values.
+func (w *writer) list(name string, values []string) {
+	escaped := make([]string, 0, len(values))
+	for _, value := range values {
+		escaped = append(escaped, textEscaper.Replace(value))
+	}
+	w.line(name, strings.Join(escaped, ","))
+}
+
+// time writes a DATE-TIME property in UTC.
+func (w *writer) time(name string, t time.Time) {
+	w.line(name, t.UTC().Format("20060102T150405Z"))
+}
+
+func (w *writer) bytes() []byte {
+	return w.buf.Bytes()
+}
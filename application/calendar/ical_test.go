@@ -0,0 +1,128 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriterLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "short",
+			value:    "Walk",
+			expected: "SUMMARY:Walk\r\n",
+		},
+		{
+			name:     "exactly one line",
+			value:    strings.Repeat("a", 75-len("SUMMARY:")),
+			expected: "SUMMARY:" + strings.Repeat("a", 67) + "\r\n",
+		},
+		{
+			name:     "one octet too long",
+			value:    strings.Repeat("a", 68),
+			expected: "SUMMARY:" + strings.Repeat("a", 67) + "\r\n a\r\n",
+		},
+		{
+			name:  "continuation lines count their space",
+			value: strings.Repeat("a", 67+74+1),
+			expected: "SUMMARY:" + strings.Repeat("a", 67) + "\r\n " +
+				strings.Repeat("a", 74) + "\r\n a\r\n",
+		},
+		{
+			// é takes two octets; the one straddling the limit moves to
+			// the next line whole.
+			name:     "never splits a character",
+			value:    strings.Repeat("a", 66) + "é",
+			expected: "SUMMARY:" + strings.Repeat("a", 66) + "\r\n é\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w writer
+			w.line("SUMMARY", tt.value)
+
+			if got := string(w.bytes()); got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWriterFoldsLongText(t *testing.T) {
+	value := strings.Repeat("Rex 🐕 mag Würstchen; ", 40)
+
+	var w writer
+	w.text("DESCRIPTION", value)
+
+	out := string(w.bytes())
+	if !strings.HasSuffix(out, "\r\n") {
+		t.Fatalf("expected the line to end with CRLF, got %q", out)
+	}
+	for _, l := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(l) > maxLineOctets {
+			t.Fatalf("expected at most %d octets, got %d in %q", maxLineOctets, len(l), l)
+		}
+		if !utf8.ValidString(l) {
+			t.Fatalf("expected whole characters on every line, got %q", l)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", "")
+	if expected := "DESCRIPTION:" + textEscaper.Replace(value); unfolded != expected {
+		t.Fatalf("expected unfolding to restore %q, got %q", expected, unfolded)
+	}
+}
+
+func TestWriterText(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "plain", value: "Walk Rex", expected: `Walk Rex`},
+		{name: "backslash", value: `C:\dogs`, expected: `C:\\dogs`},
+		{name: "separators", value: "Walk; then feed, then play", expected: `Walk\; then feed\, then play`},
+		{name: "newlines", value: "one\ntwo\r\nthree", expected: `one\ntwo\nthree`},
+		{name: "bare carriage return", value: "one\rtwo", expected: `onetwo`},
+		{name: "colon needs no escape", value: "Note: bring a leash", expected: `Note: bring a leash`},
+		{name: "escaped once", value: `\,`, expected: `\\\,`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w writer
+			w.text("DESCRIPTION", tt.value)
+
+			if got, expected := string(w.bytes()), "DESCRIPTION:"+tt.expected+"\r\n"; got != expected {
+				t.Fatalf("expected %q, got %q", expected, got)
+			}
+		})
+	}
+}
+
+func TestWriterList(t *testing.T) {
+	var w writer
+	w.list("CATEGORIES", []string{"Walk", "Drop-in, short", "Sitting"})
+
+	if got, expected := string(w.bytes()), "CATEGORIES:Walk,Drop-in\\, short,Sitting\r\n"; got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestWriterTime(t *testing.T) {
+	berlin := time.FixedZone("CET", 60*60)
+
+	var w writer
+	w.time("DTSTART", time.Date(2024, 3, 4, 9, 30, 15, 500, berlin))
+
+	if got, expected := string(w.bytes()), "DTSTART:20240304T083015Z\r\n"; got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,140 @@
+package matching
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+var weekdays = map[models.AvailabilitySlotDay]time.Weekday{
+	models.Sunday:    time.Sunday,
+	models.Monday:    time.Monday,
+	models.Tuesday:   time.Tuesday,
+	models.Wednesday: time.Wednesday,
+	models.Thursday:  time.Thursday,
+	models.Friday:    time.Friday,
+	models.Saturday:  time.Saturday,
+}
+
+// slot is a weekly slot with its times of day in minutes after midnight.
+type slot struct {
+	day   time.Weekday
+	start int
+	end   int
+}
+
+// Calendar answers whether a sitter is free, from their availability.
+type Calendar struct {
+	location  *time.Location
+	slots     []slot
+	blackouts []domain.Interval
+}
+
+// NewCalendar reads the time zone, weekly slots and blackouts of
+// availability. It returns a *domain.ValidationError when any of them is
+// invalid. The activities and dog sizes are left to the caller.
+func NewCalendar(availability models.Availability) (*Calendar, error) {
+	var v domain.ValidationError
+
+	location := time.UTC
+	switch loaded, err := time.LoadLocation(availability.Timezone); {
+	case availability.Timezone == "":
+		v.Add("timezone", "is required")
+	case err != nil || availability.Timezone == "Local":
+		v.Add("timezone", "unknown time zone "+availability.Timezone)
+	default:
+		location = loaded
+	}
+
+	c := &Calendar{location: location}
+	for i, s := range availability.WeeklySlots {
+		field := fmt.Sprintf("weekly_slots[%d]", i)
+		day, ok := weekdays[s.Day]
+		if !ok {
+			v.Add(field+".day", "unknown day "+string(s.Day))
+		}
+		start, okStart := parseClock(s.Start, false)
+		if !okStart {
+			v.Add(field+".start", "must be a time of day as HH:MM")
+		}
+		end, okEnd := parseClock(s.End, true)
+		if !okEnd {
+			v.Add(field+".end", "must be a time of day as HH:MM, or 24:00")
+		}
+		if okStart && okEnd && end <= start {
+			v.Add(field+".end", "must be after start")
+		}
+		c.slots = append(c.slots, slot{day: day, start: start, end: end})
+	}
+	if availability.Blackouts != nil {
+		for i, blackout := range *availability.Blackouts {
+			if !blackout.EndsAt.After(blackout.StartsAt) {
+				v.Add(fmt.Sprintf("blackouts[%d].ends_at", i), "must be after starts_at")
+			}
+			c.blackouts = append(c.blackouts, domain.Interval{Start: blackout.StartsAt, End: blackout.EndsAt})
+		}
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Free reports whether the weekly slots cover the whole of interval and no
+// blackout overlaps it.
+func (c *Calendar) Free(interval domain.Interval) bool {
+	for _, blackout := range c.blackouts {
+		if blackout.Overlaps(interval) {
+			return false
+		}
+	}
+
+	// Walk from slot to slot until the end of interval, failing at the
+	// first instant no slot covers.
+	t := interval.Start.In(c.location)
+	for t.Before(interval.End) {
+		next, ok := c.coveredUntil(t)
+		if !ok {
+			return false
+		}
+		t = next
+	}
+
+	return true
+}
+
+// coveredUntil returns the latest end of the slots open at t.
+func (c *Calendar) coveredUntil(t time.Time) (time.Time, bool) {
+	year, month, day := t.Date()
+	var until time.Time
+	for _, s := range c.slots {
+		if s.day != t.Weekday() {
+			continue
+		}
+		start := time.Date(year, month, day, 0, s.start, 0, 0, c.location)
+		end := time.Date(year, month, day, 0, s.end, 0, 0, c.location)
+		if !start.After(t) && end.After(t) && end.After(until) {
+			until = end
+		}
+	}
+
+	return until, !until.IsZero()
+}
+
+// parseClock returns the minutes after midnight of an HH:MM time of day.
+// allowEndOfDay admits 24:00.
+func parseClock(s string, allowEndOfDay bool) (int, bool) {
+	if allowEndOfDay && s == "24:00" {
+		return 24 * 60, true
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+
+	return t.Hour()*60 + t.Minute(), true
+}
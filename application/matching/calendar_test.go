@@ -0,0 +1,135 @@
+package matching
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+func utc(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestCalendarFree(t *testing.T) {
+	// Berlin is UTC+1 until 2024-03-31 and UTC+2 after. 2024-03-04 and
+	// 2024-04-01 are Mondays.
+	calendar, err := NewCalendar(models.Availability{
+		Timezone: "Europe/Berlin",
+		WeeklySlots: []models.AvailabilitySlot{
+			{Day: models.Monday, Start: "09:00", End: "17:00"},
+			{Day: models.Monday, Start: "16:00", End: "24:00"},
+			{Day: models.Tuesday, Start: "00:00", End: "06:00"},
+			{Day: models.Wednesday, Start: "10:00", End: "12:00"},
+		},
+		Blackouts: &[]models.AvailabilityBlackout{
+			{StartsAt: utc(2024, 3, 11, 11, 0), EndsAt: utc(2024, 3, 11, 12, 0)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		expected bool
+	}{
+		{name: "inside a slot", start: utc(2024, 3, 4, 9, 0), end: utc(2024, 3, 4, 10, 0), expected: true},
+		{name: "the whole slot", start: utc(2024, 3, 6, 9, 0), end: utc(2024, 3, 6, 11, 0), expected: true},
+		{name: "slot times are local", start: utc(2024, 3, 6, 10, 0), end: utc(2024, 3, 6, 12, 0)},
+		{name: "starts before the slot", start: utc(2024, 3, 4, 7, 30), end: utc(2024, 3, 4, 9, 0)},
+		{name: "ends after the slot", start: utc(2024, 3, 6, 10, 0), end: utc(2024, 3, 6, 11, 1)},
+		{name: "across overlapping slots", start: utc(2024, 3, 4, 9, 0), end: utc(2024, 3, 4, 19, 0), expected: true},
+		{name: "across midnight", start: utc(2024, 3, 4, 21, 0), end: utc(2024, 3, 5, 4, 0), expected: true},
+		{name: "past the joined slots", start: utc(2024, 3, 4, 21, 0), end: utc(2024, 3, 5, 6, 0)},
+		{name: "on a day without slots", start: utc(2024, 3, 7, 9, 0), end: utc(2024, 3, 7, 10, 0)},
+		{name: "a week apart", start: utc(2024, 3, 4, 9, 0), end: utc(2024, 3, 11, 9, 0)},
+		{name: "before a blackout", start: utc(2024, 3, 11, 9, 0), end: utc(2024, 3, 11, 11, 0), expected: true},
+		{name: "during a blackout", start: utc(2024, 3, 11, 11, 30), end: utc(2024, 3, 11, 13, 0)},
+		{name: "after a blackout", start: utc(2024, 3, 11, 12, 0), end: utc(2024, 3, 11, 13, 0), expected: true},
+		{name: "slots follow summer time", start: utc(2024, 4, 3, 8, 0), end: utc(2024, 4, 3, 10, 0), expected: true},
+		{name: "winter times miss summer slots", start: utc(2024, 4, 3, 9, 0), end: utc(2024, 4, 3, 11, 0)},
+		{name: "across midnight in summer time", start: utc(2024, 4, 1, 20, 0), end: utc(2024, 4, 2, 4, 0), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calendar.Free(domain.Interval{Start: tt.start, End: tt.end}); got != tt.expected {
+				t.Fatalf("expected free %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewCalendarValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		availability models.Availability
+		fields       []string
+	}{
+		{
+			name: "valid",
+			availability: models.Availability{
+				Timezone:    "UTC",
+				WeeklySlots: []models.AvailabilitySlot{{Day: models.Sunday, Start: "00:00", End: "24:00"}},
+			},
+		},
+		{
+			name: "every broken field",
+			availability: models.Availability{
+				WeeklySlots: []models.AvailabilitySlot{
+					{Day: "Someday", Start: "9am", End: "25:00"},
+					{Day: models.Monday, Start: "24:00", End: "10:00"},
+					{Day: models.Monday, Start: "10:00", End: "10:00"},
+				},
+				Blackouts: &[]models.AvailabilityBlackout{
+					{StartsAt: utc(2024, 3, 4, 9, 0), EndsAt: utc(2024, 3, 4, 9, 0)},
+				},
+			},
+			fields: []string{
+				"timezone",
+				"weekly_slots[0].day",
+				"weekly_slots[0].start",
+				"weekly_slots[0].end",
+				"weekly_slots[1].start",
+				"weekly_slots[2].end",
+				"blackouts[0].ends_at",
+			},
+		},
+		{
+			name:         "unknown time zone",
+			availability: models.Availability{Timezone: "Europe/Atlantis"},
+			fields:       []string{"timezone"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCalendar(tt.availability)
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validation *domain.ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("expected a *domain.ValidationError, got %v", err)
+			}
+			if len(validation.Violations) != len(tt.fields) {
+				t.Fatalf("expected violations of %v, got %+v", tt.fields, validation.Violations)
+			}
+			for i, v := range validation.Violations {
+				if v.Field != tt.fields[i] {
+					t.Fatalf("expected violations of %v, got %+v", tt.fields, validation.Violations)
+				}
+			}
+		})
+	}
+}
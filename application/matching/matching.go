@@ -0,0 +1,179 @@
+// Package matching finds the sitters who are free for a job and ranks them
+// by how well they suit it.
+package matching
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/logging"
+)
+
+type Service struct {
+	availability domain.AvailabilityRepository
+	users        domain.UserRepository
+	jobs         domain.JobRepository
+	buffer       time.Duration
+}
+
+// New returns a Service that keeps matched sitters buffer away from the jobs
+// they have been accepted for.
+func New(availability domain.AvailabilityRepository, users domain.UserRepository, jobs domain.JobRepository, buffer time.Duration) *Service {
+	return &Service{
+		availability: availability,
+		users:        users,
+		jobs:         jobs,
+		buffer:       buffer,
+	}
+}
+
+// Sizes a sitter may prefer, ranked best first.
+const (
+	prefersOtherSize = iota
+	noSizePreference
+	prefersSize
+)
+
+type match struct {
+	models.SitterMatch
+	overlap int
+	size    int
+}
+
+// Match returns up to limit PetSitters who are free for the whole of job and
+// offer at least one of its activities. Sitters offering more of the
+// activities come first, then those preferring the size of every dog, then
+// those without a size preference; ties are broken by user id.
+func (s *Service) Match(ctx context.Context, job models.Job, limit int) ([]models.SitterMatch, error) {
+	availabilities, err := s.availability.GetAvailabilities(ctx, job.Activities)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := domain.Interval{Start: job.StartsAt, End: job.EndsAt}
+	var matches []match
+	for _, availability := range availabilities {
+		userId := *availability.UserId
+		if job.CreatorUserId != nil && userId == *job.CreatorUserId {
+			continue
+		}
+
+		calendar, err := NewCalendar(availability)
+		if err != nil {
+			logging.FromContext(ctx).Warn("Skipping an invalid availability", slog.String("user_id", userId), slog.Any("error", err))
+			continue
+		}
+		if !calendar.Free(interval) {
+			continue
+		}
+
+		user, err := s.users.GetUsersId(ctx, userId)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(user.Roles, models.PetSitter) {
+			continue
+		}
+
+		matches = append(matches, newMatch(user, availability, job))
+	}
+
+	busy, err := s.busy(ctx, matches, job, interval)
+	if err != nil {
+		return nil, err
+	}
+	matches = slices.DeleteFunc(matches, func(m match) bool { return busy[*m.UserId] })
+
+	// GetAvailabilities orders by user id, which the stable sort keeps for
+	// ties.
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].overlap != matches[j].overlap {
+			return matches[i].overlap > matches[j].overlap
+		}
+		return matches[i].size > matches[j].size
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	result := make([]models.SitterMatch, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.SitterMatch)
+	}
+
+	return result, nil
+}
+
+// busy returns the sitters of matches who have been accepted for a job,
+// other than job, that overlaps interval or comes within the buffer of it.
+// It looks all of them up at once.
+func (s *Service) busy(ctx context.Context, matches []match, job models.Job, interval domain.Interval) (map[string]bool, error) {
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	userIds := make([]string, 0, len(matches))
+	for _, m := range matches {
+		userIds = append(userIds, *m.UserId)
+	}
+	window := interval.Pad(s.buffer)
+	working, _, err := s.jobs.GetJobs(ctx, domain.JobQuery{
+		WorkerUserIds: userIds,
+		StartsBefore:  &window.End,
+		EndsAfter:     &window.Start,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make(map[string]bool)
+	for _, other := range working {
+		if job.Id != nil && *other.Id == *job.Id {
+			continue
+		}
+		busy[*other.WorkerUserId] = true
+	}
+
+	return busy, nil
+}
+
+func newMatch(user models.User, availability models.Availability, job models.Job) match {
+	var activities []models.JobActivities
+	for _, activity := range job.Activities {
+		if slices.Contains(availability.Activities, activity) {
+			activities = append(activities, activity)
+		}
+	}
+
+	size := noSizePreference
+	dogs := domain.JobDogs(job)
+	if len(dogs) > 0 && availability.DogSizes != nil && len(*availability.DogSizes) > 0 {
+		size = prefersSize
+		for _, dog := range dogs {
+			if !slices.Contains(*availability.DogSizes, dog.Size) {
+				size = prefersOtherSize
+			}
+		}
+	}
+	prefers := size == prefersSize
+
+	return match{
+		SitterMatch: models.SitterMatch{
+			UserId:         user.Id,
+			FullName:       &user.FullName,
+			Activities:     &activities,
+			PrefersDogSize: &prefers,
+		},
+		overlap: len(activities),
+		size:    size,
+	}
+}
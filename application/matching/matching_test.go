@@ -0,0 +1,214 @@
+package matching
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/infrastructure/repositories/memory"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// sitter is a user offering activities every day, all day, in UTC.
+type sitter struct {
+	name       string
+	roles      []models.UserRoles
+	activities []models.JobActivities
+	dogSizes   []models.JobDogSize
+	blackout   *domain.Interval
+	// workingAt, when set, is a job the sitter has been accepted for.
+	workingAt *domain.Interval
+}
+
+func TestMatch(t *testing.T) {
+	ctx := context.Background()
+	start := utc(2024, 3, 4, 12, 0)
+	interval := domain.Interval{Start: start, End: start.Add(2 * time.Hour)}
+	both := []models.JobActivities{models.Walk, models.Daycare}
+	sitterRole := []models.UserRoles{models.PetSitter}
+
+	sitters := []sitter{
+		{name: "Owner", roles: []models.UserRoles{models.PetOwner, models.PetSitter}, activities: both},
+		{name: "Ann", roles: sitterRole, activities: both, dogSizes: []models.JobDogSize{models.Small, models.Medium}},
+		{name: "Ben", roles: sitterRole, activities: both},
+		{name: "Cid", roles: sitterRole, activities: both, dogSizes: []models.JobDogSize{models.Small}},
+		{name: "Dee", roles: sitterRole, activities: []models.JobActivities{models.Walk}, dogSizes: []models.JobDogSize{models.Small, models.Medium}},
+		{name: "Eve", roles: sitterRole, activities: []models.JobActivities{models.Boarding}},
+		{name: "Fay", roles: sitterRole, activities: both, blackout: &domain.Interval{Start: start.Add(time.Hour), End: start.Add(3 * time.Hour)}},
+		{name: "Gus", roles: sitterRole, activities: both, workingAt: &domain.Interval{Start: start.Add(-2 * time.Hour), End: start.Add(-30 * time.Minute)}},
+		{name: "Hal", roles: []models.UserRoles{models.PetOwner}, activities: both},
+		{name: "Ivy", roles: sitterRole, activities: []models.JobActivities{models.Walk}, workingAt: &domain.Interval{Start: start.Add(-4 * time.Hour), End: start.Add(-2 * time.Hour)}},
+	}
+
+	users := memory.NewUserRepository()
+	availability := memory.NewAvailabilityRepository()
+	jobs := memory.NewJobRepository()
+	applications := memory.NewJobApplicationRepository(jobs)
+
+	ids := make(map[string]string)
+	names := make(map[string]string)
+	for _, s := range sitters {
+		user, err := users.PostUsers(ctx, models.User{
+			Email:    openapi_types.Email(s.name + "@example.com"),
+			FullName: s.name,
+			Roles:    s.roles,
+		}, "hash")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		userId := *user.Id
+		ids[s.name] = userId
+		names[userId] = s.name
+
+		var slots []models.AvailabilitySlot
+		for day := range weekdays {
+			slots = append(slots, models.AvailabilitySlot{Day: day, Start: "00:00", End: "24:00"})
+		}
+		a := models.Availability{Timezone: "UTC", Activities: s.activities, WeeklySlots: slots}
+		if s.dogSizes != nil {
+			a.DogSizes = &s.dogSizes
+		}
+		if s.blackout != nil {
+			a.Blackouts = &[]models.AvailabilityBlackout{{StartsAt: s.blackout.Start, EndsAt: s.blackout.End}}
+		}
+		if _, err := availability.PutAvailability(ctx, userId, a); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if s.workingAt != nil {
+			other, err := jobs.PostJobs(ctx, models.Job{
+				CreatorUserId: ptr(ids["Owner"]),
+				Description:   "Another walk",
+				Activities:    []models.JobActivities{models.Walk},
+				StartsAt:      s.workingAt.Start,
+				EndsAt:        s.workingAt.End,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			application, err := applications.CreateJobApplication(ctx, models.JobApplication{JobId: other.Id, UserId: &userId})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := applications.AcceptJobApplication(ctx, *application.Id, 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	job, err := jobs.PostJobs(ctx, models.Job{
+		CreatorUserId: ptr(ids["Owner"]),
+		Description:   "Walk Rex and Fido",
+		Dogs: &[]models.JobDog{
+			{Name: ptr("Rex"), Breed: "Beagle", Size: models.Small, YearsOld: 3},
+			{Name: ptr("Fido"), Breed: "Collie", Size: models.Medium, YearsOld: 5},
+		},
+		Activities: both,
+		StartsAt:   interval.Start,
+		EndsAt:     interval.End,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	service := New(availability, users, jobs, time.Hour)
+
+	tests := []struct {
+		name     string
+		limit    int
+		expected []string
+	}{
+		{
+			// Ann, Ben and Cid offer both activities and are ranked by
+			// their size preference, ahead of Dee and Ivy, who only walk.
+			// Ivy's other job ends more than the buffer earlier. Eve offers
+			// neither activity, Fay is away, Gus works a job within the
+			// buffer, Hal is no sitter and Owner posted the job.
+			name:     "ranked",
+			limit:    10,
+			expected: []string{"Ann", "Ben", "Cid", "Dee", "Ivy"},
+		},
+		{
+			name:     "limited",
+			limit:    2,
+			expected: []string{"Ann", "Ben"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := service.Match(ctx, job, tt.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, m := range matches {
+				got = append(got, names[*m.UserId])
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Fatalf("expected %v, got %v", tt.expected, got)
+				}
+			}
+		})
+	}
+
+	t.Run("match details", func(t *testing.T) {
+		matches, err := service.Match(ctx, job, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := map[string]struct {
+			activities int
+			prefers    bool
+		}{
+			"Ann": {activities: 2, prefers: true},
+			"Ben": {activities: 2},
+			"Cid": {activities: 2},
+			"Dee": {activities: 1, prefers: true},
+			"Ivy": {activities: 1},
+		}
+		for _, m := range matches {
+			name := names[*m.UserId]
+			if *m.FullName != name {
+				t.Fatalf("expected full name %s, got %s", name, *m.FullName)
+			}
+			if len(*m.Activities) != expected[name].activities {
+				t.Fatalf("expected %s to offer %d activities, got %v", name, expected[name].activities, *m.Activities)
+			}
+			if *m.PrefersDogSize != expected[name].prefers {
+				t.Fatalf("expected %s to prefer the dog size %t, got %t", name, expected[name].prefers, *m.PrefersDogSize)
+			}
+		}
+	})
+
+	t.Run("ties keep user id order", func(t *testing.T) {
+		single := job
+		single.Activities = []models.JobActivities{models.Walk}
+		single.Dogs = nil
+
+		matches, err := service.Match(ctx, single, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(matches) != 5 {
+			t.Fatalf("expected 5 matches, got %d", len(matches))
+		}
+		for i := 1; i < len(matches); i++ {
+			if *matches[i-1].UserId > *matches[i].UserId {
+				t.Fatalf("expected matches in user id order, got %s before %s", names[*matches[i-1].UserId], names[*matches[i].UserId])
+			}
+		}
+	})
+}
@@ -0,0 +1,168 @@
+// Package metrics holds the Prometheus collectors the service exports on
+// /metrics: HTTP traffic by OpenAPI operation, MongoDB commands and a few
+// domain counters.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const namespace = "agentco"
+
+// NoOperation labels requests that are not for an operation of the spec,
+// such as probes and unknown paths.
+const NoOperation = "none"
+
+// OtherMethod labels requests made with a method outside the standard set,
+// so that made-up methods cannot create new series.
+const OtherMethod = "OTHER"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	requestsInFlight prometheus.Gauge
+
+	mongoDuration *prometheus.HistogramVec
+	mongoErrors   *prometheus.CounterVec
+
+	jobsPosted           prometheus.Counter
+	applicationsCreated  prometheus.Counter
+	applicationsAccepted prometheus.Counter
+}
+
+// New registers the collectors, along with the Go runtime and process
+// collectors, on a registry of their own.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by OpenAPI operation, method and status code.",
+		}, []string{"operation", "method", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by OpenAPI operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		requestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "HTTP requests being served.",
+		}),
+		mongoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "mongo",
+			Name:      "command_duration_seconds",
+			Help:      "MongoDB command latency by command name.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"command"}),
+		mongoErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "mongo",
+			Name:      "command_errors_total",
+			Help:      "Failed MongoDB commands by command name.",
+		}, []string{"command"}),
+		jobsPosted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_posted_total",
+			Help:      "Jobs posted by pet owners.",
+		}),
+		applicationsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "job_applications_created_total",
+			Help:      "Job applications made by pet sitters.",
+		}),
+		applicationsAccepted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "job_applications_accepted_total",
+			Help:      "Job applications accepted by job creators.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.requestsInFlight,
+		m.mongoDuration,
+		m.mongoErrors,
+		m.jobsPosted,
+		m.applicationsCreated,
+		m.applicationsAccepted,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RequestStarted counts a request as in flight until the returned function
+// is called with its outcome.
+func (m *Metrics) RequestStarted() func(operation, method string, status int) {
+	if m == nil {
+		return func(string, string, int) {}
+	}
+
+	start := time.Now()
+	m.requestsInFlight.Inc()
+
+	return func(operation, method string, status int) {
+		m.requestsInFlight.Dec()
+		m.requests.WithLabelValues(operation, method, strconv.Itoa(status)).Inc()
+		m.requestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	}
+}
+
+// CommandMonitor returns a driver monitor recording the latency and failures
+// of every command the client sends.
+func (m *Metrics) CommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			m.mongoDuration.WithLabelValues(e.CommandName).Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			m.mongoDuration.WithLabelValues(e.CommandName).Observe(e.Duration.Seconds())
+			m.mongoErrors.WithLabelValues(e.CommandName).Inc()
+		},
+	}
+}
+
+// The domain counters may be called on a nil *Metrics, so that handlers
+// work without metrics.
+
+func (m *Metrics) JobPosted() {
+	if m != nil {
+		m.jobsPosted.Inc()
+	}
+}
+
+func (m *Metrics) ApplicationCreated() {
+	if m != nil {
+		m.applicationsCreated.Inc()
+	}
+}
+
+func (m *Metrics) ApplicationAccepted() {
+	if m != nil {
+		m.applicationsAccepted.Inc()
+	}
+}
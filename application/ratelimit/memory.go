@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many calls a MemoryStore handles between removals of
+// idle state.
+const sweepEvery = 1024
+
+// MemoryStore keeps state in process, so each instance of the service
+// enforces its limits on its own.
+type MemoryStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	failures map[string]*failures
+	calls    int
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+type failures struct {
+	count  int
+	last   time.Time
+	forget time.Duration
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*bucket),
+		failures: make(map[string]*failures),
+	}
+}
+
+func (m *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Requests), updated: now, limit: limit}
+		m.buckets[key] = b
+	}
+	b.refill(now)
+
+	result := Result{Limit: limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(limit.interval()))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((float64(limit.Requests) - b.tokens) * float64(limit.interval()))
+
+	return result, nil
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated)
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens += float64(elapsed) / float64(b.limit.interval())
+	if max := float64(b.limit.Requests); b.tokens > max {
+		b.tokens = max
+	}
+	b.updated = now
+}
+
+func (m *MemoryStore) Attempt(ctx context.Context, key string, now time.Time, backoff Backoff) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	f, ok := m.failures[key]
+	if !ok || f.expired(now) {
+		f = &failures{}
+		m.failures[key] = f
+	}
+	if until := f.last.Add(backoff.Duration(f.count)); now.Before(until) {
+		return until.Sub(now), nil
+	}
+	f.count++
+	f.last = now
+	f.forget = backoff.Forget
+
+	return 0, nil
+}
+
+func (m *MemoryStore) Clear(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.failures, key)
+	return nil
+}
+
+func (f *failures) expired(now time.Time) bool {
+	return f.forget > 0 && now.Sub(f.last) > f.forget
+}
+
+// sweep drops full buckets and forgotten failures every sweepEvery calls, so
+// that one-off clients do not accumulate.
+func (m *MemoryStore) sweep(now time.Time) {
+	m.calls++
+	if m.calls%sweepEvery != 0 {
+		return
+	}
+
+	for key, b := range m.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Requests) {
+			delete(m.buckets, key)
+		}
+	}
+	for key, f := range m.failures {
+		if f.expired(now) {
+			delete(m.failures, key)
+		}
+	}
+}
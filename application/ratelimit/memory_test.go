@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+
+	tests := []struct {
+		name       string
+		key        string
+		limit      Limit
+		at         time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+		reset      time.Duration
+	}{
+		{name: "new bucket starts full", key: "a", limit: limit, allowed: true, remaining: 2, reset: time.Second},
+		{name: "burst", key: "a", limit: limit, allowed: true, remaining: 1, reset: 2 * time.Second},
+		{name: "last token", key: "a", limit: limit, allowed: true, remaining: 0, reset: 3 * time.Second},
+		{name: "empty", key: "a", limit: limit, allowed: false, retryAfter: time.Second, reset: 3 * time.Second},
+		{name: "other keys have their own bucket", key: "b", limit: limit, allowed: true, remaining: 2, reset: time.Second},
+		{name: "half a token refilled", key: "a", limit: limit, at: 500 * time.Millisecond, allowed: false, retryAfter: 500 * time.Millisecond, reset: 2500 * time.Millisecond},
+		{name: "one token refilled", key: "a", limit: limit, at: time.Second, allowed: true, remaining: 0, reset: 3 * time.Second},
+		{name: "clock going backwards refills nothing", key: "a", limit: limit, at: 0, allowed: false, retryAfter: time.Second, reset: 3 * time.Second},
+		{name: "refill stops at the burst size", key: "a", limit: limit, at: time.Hour, allowed: true, remaining: 2, reset: time.Second},
+		{name: "a changed limit starts a new bucket", key: "a", limit: Limit{Requests: 1, Period: time.Minute}, at: time.Hour, allowed: true, remaining: 0, reset: time.Minute},
+		{name: "the changed limit applies", key: "a", limit: Limit{Requests: 1, Period: time.Minute}, at: time.Hour + 15*time.Second, allowed: false, retryAfter: 45 * time.Second, reset: 45 * time.Second},
+	}
+
+	store := NewMemoryStore()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := store.Take(ctx, tt.key, tt.limit, start.Add(tt.at))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Allowed != tt.allowed {
+				t.Fatalf("expected allowed %t, got %t", tt.allowed, result.Allowed)
+			}
+			if result.Remaining != tt.remaining {
+				t.Fatalf("expected %d remaining, got %d", tt.remaining, result.Remaining)
+			}
+			if result.RetryAfter != tt.retryAfter {
+				t.Fatalf("expected retry after %s, got %s", tt.retryAfter, result.RetryAfter)
+			}
+			if result.Reset != tt.reset {
+				t.Fatalf("expected reset %s, got %s", tt.reset, result.Reset)
+			}
+			if result.Limit != tt.limit {
+				t.Fatalf("expected limit %s, got %s", tt.limit, result.Limit)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreAttempt(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	backoff := Backoff{Threshold: 2, Base: time.Minute, Max: 3 * time.Minute, Forget: time.Hour}
+
+	tests := []struct {
+		name   string
+		key    string
+		at     time.Duration
+		clear  bool
+		locked time.Duration
+	}{
+		{name: "first failure", key: "a"},
+		{name: "below the threshold", key: "a", at: time.Second},
+		{name: "threshold reached", key: "a", at: 2 * time.Second, locked: time.Minute - time.Second},
+		{name: "locked attempts are not counted", key: "a", at: 30 * time.Second, locked: 31 * time.Second},
+		{name: "other keys are not locked", key: "b", at: 30 * time.Second},
+		{name: "lockout over", key: "a", at: 61 * time.Second},
+		{name: "lockout doubles", key: "a", at: 62 * time.Second, locked: 2*time.Minute - time.Second},
+		{name: "after the doubled lockout", key: "a", at: 181 * time.Second},
+		{name: "lockout capped", key: "a", at: 182 * time.Second, locked: 3*time.Minute - time.Second},
+		{name: "after the capped lockout", key: "a", at: 361 * time.Second},
+		{name: "failures forgotten", key: "a", at: 361*time.Second + time.Hour + time.Second},
+		{name: "counting again from one", key: "a", at: 362*time.Second + time.Hour},
+		{name: "locked again", key: "a", at: 363*time.Second + time.Hour, locked: time.Minute - time.Second},
+		{name: "cleared", key: "a", at: 364*time.Second + time.Hour, clear: true},
+	}
+
+	store := NewMemoryStore()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.clear {
+				if err := store.Clear(ctx, tt.key); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			locked, err := store.Attempt(ctx, tt.key, start.Add(tt.at), backoff)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if locked != tt.locked {
+				t.Fatalf("expected to be locked for %s, got %s", tt.locked, locked)
+			}
+		})
+	}
+}
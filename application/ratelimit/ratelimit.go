@@ -0,0 +1,189 @@
+// Package ratelimit throttles clients with token buckets and locks out
+// logins after repeated failures. Its state lives behind Store, kept in
+// memory by MemoryStore; a shared store lets several instances enforce one
+// limit.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Period, refilled continuously, with bursts of up
+// to Requests.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit reads a limit written as "<requests>/<period>", such as "10/1m".
+func ParseLimit(s string) (Limit, error) {
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q is not <requests>/<period>", s)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("limit %q needs a positive number of requests", s)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("limit %q needs a positive period", s)
+	}
+
+	return Limit{Requests: n, Period: d}, nil
+}
+
+func (l Limit) String() string {
+	return strconv.Itoa(l.Requests) + "/" + l.Period.String()
+}
+
+// interval is the time it takes to refill one token.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Seconds rounds d up to whole seconds, as Retry-After and the RateLimit
+// headers want them.
+func Seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// Result is the state of a bucket after a request was counted against it.
+type Result struct {
+	Allowed   bool
+	Limit     Limit
+	Remaining int
+	// RetryAfter is how long to wait until a request will be allowed; zero
+	// when this one was.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store holds bucket and login failure state.
+type Store interface {
+	// Take counts a request against the bucket key, created full with limit
+	// on first use.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+	// Attempt atomically checks whether key is locked out under backoff
+	// and, when it is not, records a login attempt as a failure until Clear
+	// forgets it. While locked it records nothing and returns how much
+	// longer the lockout lasts; otherwise it returns zero. Failures older
+	// than backoff.Forget are dropped.
+	Attempt(ctx context.Context, key string, now time.Time, backoff Backoff) (time.Duration, error)
+	// Clear forgets the failures of key.
+	Clear(ctx context.Context, key string) error
+}
+
+// Limiter applies a limit per operation to each client, identified by a
+// key such as its user ID or IP address. Operations without a limit of their
+// own share one Default bucket per client. Operations are matched without
+// regard to case, since configuration keys are case-insensitive, so the
+// keys of Operations must be lower case. PerIP bounds all requests from one
+// address, whoever they claim to be.
+type Limiter struct {
+	Store      Store
+	Default    Limit
+	Operations map[string]Limit
+	PerIP      Limit
+}
+
+// TakeIP counts a request from the address ip against PerIP.
+func (l *Limiter) TakeIP(ctx context.Context, ip string) (Result, error) {
+	return l.Store.Take(ctx, "ip:"+ip, l.PerIP, time.Now())
+}
+
+// Take counts a request by client for operation.
+func (l *Limiter) Take(ctx context.Context, operation, client string) (Result, error) {
+	operation = strings.ToLower(operation)
+	limit, ok := l.Operations[operation]
+	key := "operation:" + operation + ":" + client
+	if !ok {
+		limit, key = l.Default, "default:"+client
+	}
+
+	return l.Store.Take(ctx, key, limit, time.Now())
+}
+
+// ErrLocked is reported for logins attempted during a lockout.
+var ErrLocked = errors.New("too many failed logins; try again later")
+
+// LockedError is returned by Lockout.Attempt while a key is locked.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return ErrLocked.Error()
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Backoff locks a key after Threshold consecutive failures. The first
+// lockout lasts Base and every further failure doubles it, up to Max.
+// Failures are forgotten after Forget without a new one.
+type Backoff struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+	Forget    time.Duration
+}
+
+// Duration is how long failures consecutive failures lock a key for.
+func (b Backoff) Duration(failures int) time.Duration {
+	if failures < b.Threshold {
+		return 0
+	}
+
+	d := float64(b.Base) * math.Pow(2, float64(failures-b.Threshold))
+	if d > float64(b.Max) {
+		return b.Max
+	}
+
+	return time.Duration(d)
+}
+
+// Lockout blocks logins for a key under Backoff. A success clears the
+// failures.
+type Lockout struct {
+	Store   Store
+	Backoff Backoff
+}
+
+// Attempt returns a *LockedError when key is locked out. Otherwise it counts
+// the login as failed until Succeeded clears it, before the password is
+// checked, so that concurrent guesses cannot all slip in under the
+// threshold. A nil *Lockout never locks.
+func (l *Lockout) Attempt(ctx context.Context, key string) error {
+	if l == nil {
+		return nil
+	}
+
+	locked, err := l.Store.Attempt(ctx, key, time.Now(), l.Backoff)
+	if err != nil {
+		return err
+	}
+	if locked > 0 {
+		return &LockedError{RetryAfter: locked}
+	}
+
+	return nil
+}
+
+// Succeeded clears the failures of key.
+func (l *Lockout) Succeeded(ctx context.Context, key string) error {
+	if l == nil {
+		return nil
+	}
+
+	return l.Store.Clear(ctx, key)
+}
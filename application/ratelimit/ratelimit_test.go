@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Limit
+		err      bool
+	}{
+		{input: "10/1m", expected: Limit{Requests: 10, Period: time.Minute}},
+		{input: " 5 / 30s ", expected: Limit{Requests: 5, Period: 30 * time.Second}},
+		{input: "10", err: true},
+		{input: "0/1m", err: true},
+		{input: "-1/1m", err: true},
+		{input: "ten/1m", err: true},
+		{input: "10/0s", err: true},
+		{input: "10/forever", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			limit, err := ParseLimit(tt.input)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", limit)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if limit != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, limit)
+			}
+		})
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected int
+	}{
+		{d: 0, expected: 0},
+		{d: time.Millisecond, expected: 1},
+		{d: time.Second, expected: 1},
+		{d: 1500 * time.Millisecond, expected: 2},
+	}
+
+	for _, tt := range tests {
+		if got := Seconds(tt.d); got != tt.expected {
+			t.Fatalf("expected %s to be %d seconds, got %d", tt.d, tt.expected, got)
+		}
+	}
+}
+
+func TestBackoffDuration(t *testing.T) {
+	backoff := Backoff{Threshold: 3, Base: time.Minute, Max: 10 * time.Minute}
+
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{failures: 0, expected: 0},
+		{failures: 2, expected: 0},
+		{failures: 3, expected: time.Minute},
+		{failures: 4, expected: 2 * time.Minute},
+		{failures: 6, expected: 8 * time.Minute},
+		{failures: 7, expected: 10 * time.Minute},
+		{failures: 1000, expected: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := backoff.Duration(tt.failures); got != tt.expected {
+			t.Fatalf("expected %d failures to lock for %s, got %s", tt.failures, tt.expected, got)
+		}
+	}
+}
+
+func TestLimiterTake(t *testing.T) {
+	ctx := context.Background()
+	limiter := &Limiter{
+		Store:      NewMemoryStore(),
+		Default:    Limit{Requests: 1, Period: time.Hour},
+		Operations: map[string]Limit{"post_sessions": {Requests: 2, Period: time.Hour}},
+		PerIP:      Limit{Requests: 1, Period: time.Hour},
+	}
+
+	tests := []struct {
+		name      string
+		operation string
+		client    string
+		allowed   bool
+	}{
+		{name: "own limit", operation: "post_sessions", client: "1.2.3.4", allowed: true},
+		{name: "operations match without case", operation: "POST_SESSIONS", client: "1.2.3.4", allowed: true},
+		{name: "own limit used up", operation: "post_sessions", client: "1.2.3.4"},
+		{name: "default bucket", operation: "get_jobs", client: "user-1", allowed: true},
+		{name: "default bucket is shared", operation: "get_users_id", client: "user-1"},
+		{name: "clients are limited apart", operation: "get_jobs", client: "user-2", allowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := limiter.Take(ctx, tt.operation, tt.client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Allowed != tt.allowed {
+				t.Fatalf("expected allowed %t, got %t", tt.allowed, result.Allowed)
+			}
+		})
+	}
+
+	t.Run("per ip bucket is separate", func(t *testing.T) {
+		result, err := limiter.TakeIP(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Allowed {
+			t.Fatal("expected the per ip bucket not to share the default one")
+		}
+	})
+}
+
+func TestLockout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nil never locks", func(t *testing.T) {
+		var lockout *Lockout
+		for i := 0; i < 10; i++ {
+			if err := lockout.Attempt(ctx, "jane@example.com"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if err := lockout.Succeeded(ctx, "jane@example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("locks after the threshold", func(t *testing.T) {
+		lockout := &Lockout{
+			Store:   NewMemoryStore(),
+			Backoff: Backoff{Threshold: 2, Base: time.Minute, Max: time.Hour, Forget: time.Hour},
+		}
+
+		for i := 0; i < 2; i++ {
+			if err := lockout.Attempt(ctx, "jane@example.com"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		err := lockout.Attempt(ctx, "jane@example.com")
+		var locked *LockedError
+		if !errors.As(err, &locked) || !errors.Is(err, ErrLocked) {
+			t.Fatalf("expected a *LockedError, got %v", err)
+		}
+		if locked.RetryAfter <= 0 || locked.RetryAfter > time.Minute {
+			t.Fatalf("expected to retry within a minute, got %s", locked.RetryAfter)
+		}
+
+		if err := lockout.Succeeded(ctx, "jane@example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := lockout.Attempt(ctx, "jane@example.com"); err != nil {
+			t.Fatalf("expected a success to clear the lockout, got %v", err)
+		}
+	})
+}
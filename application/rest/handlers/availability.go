@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/bersennaidoo/agentco/application/matching"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+func (h *Handler) GetUserAvailability(w http.ResponseWriter, r *http.Request, id string) {
+	availability, err := h.availabilityRepository.GetAvailability(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("availability not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, availability)
+}
+
+// PutUserAvailability replaces the availability of a PetSitter. Other users
+// have no availability and get 409 Conflict.
+func (h *Handler) PutUserAvailability(w http.ResponseWriter, r *http.Request, id string) {
+	var body models.PutUserAvailabilityJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	if err := validateAvailability(body); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	user, err := h.userRepository.GetUsersId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("user not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	if !slices.Contains(user.Roles, models.PetSitter) {
+		problem.Write(w, r, problem.Conflict("only PetSitter users have an availability"))
+		return
+	}
+
+	availability, err := h.availabilityRepository.PutAvailability(r.Context(), id, body)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, availability)
+}
+
+// GetJobMatches lists the sitters who are free for the job, best suited
+// first.
+func (h *Handler) GetJobMatches(w http.ResponseWriter, r *http.Request, id string, params models.GetJobMatchesParams) {
+	limit := h.pagination.limit(params.Limit)
+	if limit < 1 {
+		problem.Write(w, r, problem.InvalidField(models.Query, "limit", "must be at least 1"))
+		return
+	}
+
+	job, err := h.jobRepository.GetJobsId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	matches, err := h.matching.Match(r.Context(), job, limit)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, matches)
+}
+
+// validateAvailability checks the activities and dog sizes of availability,
+// and then its time zone, slots and blackouts. It returns a
+// *domain.ValidationError listing every problem, or nil when the
+// availability is valid.
+func validateAvailability(availability models.Availability) error {
+	var v domain.ValidationError
+
+	if len(availability.Activities) == 0 {
+		v.Add("activities", "must contain at least one activity")
+	}
+	for _, activity := range availability.Activities {
+		if !validActivity(activity) {
+			v.Add("activities", "unknown activity "+string(activity))
+		}
+	}
+	if availability.DogSizes != nil {
+		for _, size := range *availability.DogSizes {
+			if !validDogSize(size) {
+				v.Add("dog_sizes", "unknown dog size "+string(size))
+			}
+		}
+	}
+
+	_, err := matching.NewCalendar(availability)
+	var invalid *domain.ValidationError
+	if errors.As(err, &invalid) {
+		v.Violations = append(v.Violations, invalid.Violations...)
+	}
+
+	return v.Err()
+}
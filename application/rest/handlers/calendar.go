@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bersennaidoo/agentco/application/calendar"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+// calendarPageSize is how many jobs are read at a time to build a feed.
+const calendarPageSize = 100
+
+// PostCalendarFeed issues a new token for the user's calendar feed and
+// returns the URL to subscribe to.
+func (h *Handler) PostCalendarFeed(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := h.userRepository.GetUsersId(r.Context(), id); errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("user not found"))
+		return
+	} else if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	token, err := h.calendar.Issue(r.Context(), id)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	feedURL := h.links.base(r) + "/users/" + url.PathEscape(id) + "/calendar.ics?" + url.Values{"token": {token}}.Encode()
+	writeJSON(w, http.StatusOK, models.CalendarFeed{Url: &feedURL})
+}
+
+func (h *Handler) DeleteCalendarFeed(w http.ResponseWriter, r *http.Request, id string) {
+	err := h.calendar.Revoke(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("calendar feed not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCalendarFeed serves the iCalendar feed of the jobs GetJobsForUser lists
+// for the user, from calendar.History ago on. A token that does not open the
+// user's feed gets 404, as does a feed of a deleted user.
+func (h *Handler) GetCalendarFeed(w http.ResponseWriter, r *http.Request, id string, params models.GetCalendarFeedParams) {
+	feed, err := h.calendar.Open(r.Context(), id, params.Token)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("calendar feed not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	user, err := h.userRepository.GetUsersId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("calendar feed not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	query, _ := jobsForUserQuery(user, nil)
+	since := time.Now().Add(-calendar.History)
+	query.StartsAfter = &since
+	jobs, err := h.allJobs(r.Context(), query)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	base := h.links.base(r)
+	body, err := h.calendar.Publish(r.Context(), feed, jobs, func(jobId string) string {
+		return base + "/jobs/" + url.PathEscape(jobId)
+	})
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="agentco.ics"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// allJobs reads every page of the jobs selected by query.
+func (h *Handler) allJobs(ctx context.Context, query domain.JobQuery) ([]models.Job, error) {
+	query.Limit = calendarPageSize
+
+	var all []models.Job
+	for {
+		jobs, total, err := h.jobRepository.GetJobs(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, jobs...)
+		query.Offset += len(jobs)
+		if len(jobs) == 0 || query.Offset >= total {
+			return all, nil
+		}
+	}
+}
@@ -1,68 +1,124 @@
 package handlers
 
 import (
-	"fmt"
+	"encoding/json"
 	"net/http"
-
-	"github.com/bersennaidoo/agentco/domain/models"
-	"github.com/bersennaidoo/agentco/infrastructure/repositories/mongo"
+	"strings"
+	"time"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/calendar"
+	"github.com/bersennaidoo/agentco/application/matching"
+	"github.com/bersennaidoo/agentco/application/metrics"
+	"github.com/bersennaidoo/agentco/application/ratelimit"
+	"github.com/bersennaidoo/agentco/application/series"
+	"github.com/bersennaidoo/agentco/domain"
 )
 
-type Handler struct {
-	userRepository *mongo.UserRepository
+// Pagination bounds the page sizes list endpoints hand out.
+type Pagination struct {
+	DefaultLimit int
+	MaxLimit     int
 }
 
-func New(userRepository *mongo.UserRepository) *Handler {
-	return &Handler{
-		userRepository: userRepository,
+// limit returns the page size to use for a requested limit.
+func (p Pagination) limit(requested *int) int {
+	limit := p.DefaultLimit
+	if requested != nil {
+		limit = *requested
+	}
+	if p.MaxLimit > 0 && limit > p.MaxLimit {
+		limit = p.MaxLimit
 	}
-}
-
-func (h *Handler) DeleteJobApplication(w http.ResponseWriter, r *http.Request, id string) {
-}
-
-func (h *Handler) UpdateJobApplication(w http.ResponseWriter, r *http.Request, id string) {
-}
-
-func (h *Handler) GetJobs(w http.ResponseWriter, r *http.Request, params models.GetJobsParams) {
-}
-
-func (h *Handler) PostJobs(w http.ResponseWriter, r *http.Request) {
-}
-
-func (h *Handler) DeleteJobsId(w http.ResponseWriter, r *http.Request, id string) {
-}
-
-func (h *Handler) GetJobsId(w http.ResponseWriter, r *http.Request, id string) {
-}
-
-func (h *Handler) PutJobsId(w http.ResponseWriter, r *http.Request, id string) {
-}
-
-func (h *Handler) GetApplicationsByJobId(w http.ResponseWriter, r *http.Request, id string) {
-}
 
-func (h *Handler) CreateJobApplication(w http.ResponseWriter, r *http.Request, id string) {
+	return limit
 }
 
-func (h *Handler) StartSession(w http.ResponseWriter, r *http.Request) {
+// Schedule sets how jobs a sitter works must be spaced.
+type Schedule struct {
+	// Buffer is the least time between the end of one job and the start of
+	// the next.
+	Buffer time.Duration
 }
 
-func (h *Handler) PostUsers(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, "Hello from PostUsers")
+// Links sets how the absolute URLs handed to clients are built.
+type Links struct {
+	// BaseURL is the public URL of the API. When empty, the scheme and host
+	// of each request are used.
+	BaseURL string
 }
 
-func (h *Handler) DeleteUsersId(w http.ResponseWriter, r *http.Request, id string) {
-}
+// base returns the URL that paths handed out in response to r are relative
+// to, without a trailing slash.
+func (l Links) base(r *http.Request) string {
+	if l.BaseURL != "" {
+		return strings.TrimSuffix(l.BaseURL, "/")
+	}
 
-func (h *Handler) GetUsersId(w http.ResponseWriter, r *http.Request, id string) {
-}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
 
-func (h *Handler) PutUsersId(w http.ResponseWriter, r *http.Request, id string) {
+	return scheme + "://" + r.Host
 }
 
-func (h *Handler) GetJobApplicationsForUser(w http.ResponseWriter, r *http.Request, id string) {
+type Handler struct {
+	userRepository           domain.UserRepository
+	jobRepository            domain.JobRepository
+	jobApplicationRepository domain.JobApplicationRepository
+	availabilityRepository   domain.AvailabilityRepository
+	petRepository            domain.PetRepository
+	series                   *series.Service
+	matching                 *matching.Service
+	calendar                 *calendar.Service
+	credentials              *auth.Credentials
+	sessions                 *auth.Sessions
+	pagination               Pagination
+	schedule                 Schedule
+	links                    Links
+	metrics                  *metrics.Metrics
+	lockout                  *ratelimit.Lockout
+}
+
+func New(
+	userRepository domain.UserRepository,
+	jobRepository domain.JobRepository,
+	jobApplicationRepository domain.JobApplicationRepository,
+	availabilityRepository domain.AvailabilityRepository,
+	petRepository domain.PetRepository,
+	series *series.Service,
+	matching *matching.Service,
+	calendar *calendar.Service,
+	credentials *auth.Credentials,
+	sessions *auth.Sessions,
+	pagination Pagination,
+	schedule Schedule,
+	links Links,
+	metrics *metrics.Metrics,
+	lockout *ratelimit.Lockout,
+) *Handler {
+	return &Handler{
+		userRepository:           userRepository,
+		jobRepository:            jobRepository,
+		jobApplicationRepository: jobApplicationRepository,
+		availabilityRepository:   availabilityRepository,
+		petRepository:            petRepository,
+		series:                   series,
+		matching:                 matching,
+		calendar:                 calendar,
+		credentials:              credentials,
+		sessions:                 sessions,
+		pagination:               pagination,
+		schedule:                 schedule,
+		links:                    links,
+		metrics:                  metrics,
+		lockout:                  lockout,
+	}
 }
 
-func (h *Handler) GetJobsForUser(w http.ResponseWriter, r *http.Request, id string) {
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
 }
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DeleteJobApplication withdraws an application. Withdrawing an accepted
+// application reopens the job.
+func (h *Handler) DeleteJobApplication(w http.ResponseWriter, r *http.Request, id string) {
+	err := h.jobApplicationRepository.WithdrawJobApplication(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job application not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateJobApplication lets the job creator accept or deny a pending
+// application. It responds with all applications of the job, since accepting
+// one denies the others. An applicant already working a job that overlaps
+// this one, or comes within the scheduling buffer of it, cannot be accepted.
+func (h *Handler) UpdateJobApplication(w http.ResponseWriter, r *http.Request, id string) {
+	var body models.UpdateJobApplicationJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+	if body.Status == nil {
+		problem.Write(w, r, problem.InvalidField(models.Body, "status", "is required"))
+		return
+	}
+
+	var application models.JobApplication
+	var err error
+	switch *body.Status {
+	case models.ACCEPTED:
+		application, err = h.jobApplicationRepository.AcceptJobApplication(r.Context(), id, h.schedule.Buffer)
+	case models.DENIED:
+		application, err = h.jobApplicationRepository.DenyJobApplication(r.Context(), id)
+	case models.APPLYING:
+		problem.Write(w, r, problem.Conflict("an application cannot be moved back to APPLYING"))
+		return
+	default:
+		problem.Write(w, r, problem.InvalidField(models.Body, "status", "unknown status "+string(*body.Status)))
+		return
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job application not found"))
+		return
+	}
+	if errors.Is(err, domain.ErrConflict) {
+		problem.Write(w, r, problem.Conflict(err.Error()))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	trace.SpanFromContext(r.Context()).SetAttributes(
+		tracing.JobIDKey.String(*application.JobId),
+		tracing.UserIDKey.String(*application.UserId),
+	)
+	if *body.Status == models.ACCEPTED {
+		h.metrics.ApplicationAccepted()
+	}
+
+	applications, err := h.jobApplicationRepository.GetApplicationsByJobId(r.Context(), *application.JobId)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, applications)
+}
+
+func (h *Handler) GetApplicationsByJobId(w http.ResponseWriter, r *http.Request, id string) {
+	applications, err := h.jobApplicationRepository.GetApplicationsByJobId(r.Context(), id)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, applications)
+}
+
+// CreateJobApplication applies the calling sitter to an open job. Sitters
+// cannot apply to a job they posted themselves or apply to a job twice.
+func (h *Handler) CreateJobApplication(w http.ResponseWriter, r *http.Request, id string) {
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	job, err := h.jobRepository.GetJobsId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	if *job.CreatorUserId == principal.UserId {
+		problem.Write(w, r, problem.Forbidden("you cannot apply to your own job"))
+		return
+	}
+	if job.WorkerUserId != nil {
+		problem.Write(w, r, problem.Conflict("job has already been filled"))
+		return
+	}
+
+	application, err := h.jobApplicationRepository.CreateJobApplication(r.Context(), models.JobApplication{
+		JobId:  job.Id,
+		UserId: &principal.UserId,
+	})
+	if errors.Is(err, domain.ErrConflict) {
+		problem.Write(w, r, problem.Conflict("you have already applied to this job"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	h.metrics.ApplicationCreated()
+	trace.SpanFromContext(r.Context()).SetAttributes(tracing.JobApplicationIDKey.String(*application.Id))
+	writeJSON(w, http.StatusOK, application)
+}
+
+// GetJobApplicationsForUser lists the applications a user has made, newest
+// first.
+func (h *Handler) GetJobApplicationsForUser(w http.ResponseWriter, r *http.Request, id string, params models.GetJobApplicationsForUserParams) {
+	query := domain.JobApplicationQuery{
+		UserId: id,
+		Status: params.Status,
+		Limit:  h.pagination.limit(params.Limit),
+	}
+	if params.Offset != nil {
+		query.Offset = *params.Offset
+	}
+
+	if err := validateJobApplicationQuery(query); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	if _, err := h.userRepository.GetUsersId(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			problem.Write(w, r, problem.NotFound("user not found"))
+			return
+		}
+		problem.Error(w, r, err)
+		return
+	}
+
+	applications, total, err := h.jobApplicationRepository.GetJobApplications(r.Context(), query)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	hasMore := query.Offset+len(applications) < total
+	writeJSON(w, http.StatusOK, models.InlineResponse2001{
+		Items:      &applications,
+		TotalItems: &total,
+		HasMore:    &hasMore,
+	})
+}
+
+// validateJobApplicationQuery returns a *domain.ValidationError listing every
+// invalid parameter of query, or nil when the query is valid.
+func validateJobApplicationQuery(query domain.JobApplicationQuery) error {
+	var v domain.ValidationError
+
+	if query.Limit < 1 {
+		v.Add("limit", "must be at least 1")
+	}
+	if query.Offset < 0 {
+		v.Add("offset", "must not be negative")
+	}
+	if query.Status != nil {
+		switch *query.Status {
+		case models.APPLYING, models.ACCEPTED, models.DENIED:
+		default:
+			v.Add("status", "unknown status "+string(*query.Status))
+		}
+	}
+
+	return v.Err()
+}
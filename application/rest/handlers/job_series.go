@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/application/series"
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PostJobSeries posts a recurring job and creates its first occurrences.
+func (h *Handler) PostJobSeries(w http.ResponseWriter, r *http.Request) {
+	var body models.PostJobSeriesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	if err := validateJobSeries(body); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	body.CreatorUserId = &principal.UserId
+
+	created, err := h.series.Create(r.Context(), body)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	trace.SpanFromContext(r.Context()).SetAttributes(tracing.JobSeriesIDKey.String(*created.Id))
+	w.Header().Set("Location", "/job-series/"+*created.Id)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *Handler) GetJobSeriesId(w http.ResponseWriter, r *http.Request, id string) {
+	found, err := h.series.Get(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job series not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, found)
+}
+
+// PutJobSeriesId replaces the series and applies it to its occurrences from
+// params.From, or from now, on.
+func (h *Handler) PutJobSeriesId(w http.ResponseWriter, r *http.Request, id string, params models.PutJobSeriesIdParams) {
+	var body models.PutJobSeriesIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	if err := validateJobSeries(body); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	from := time.Now()
+	if params.From != nil {
+		from = *params.From
+	}
+
+	updated, err := h.series.Edit(r.Context(), id, body, from)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job series not found"))
+		return
+	}
+	if errors.Is(err, domain.ErrConflict) {
+		problem.Write(w, r, problem.Conflict(err.Error()))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// ApplyToJobSeries applies the calling sitter to every upcoming occurrence
+// of a series, and to the occurrences created later on.
+func (h *Handler) ApplyToJobSeries(w http.ResponseWriter, r *http.Request, id string) {
+	principal, _ := auth.PrincipalFromContext(r.Context())
+
+	found, err := h.series.Get(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job series not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	if *found.CreatorUserId == principal.UserId {
+		problem.Write(w, r, problem.Forbidden("you cannot apply to your own job series"))
+		return
+	}
+
+	applications, err := h.series.Apply(r.Context(), id, principal.UserId)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	for range applications {
+		h.metrics.ApplicationCreated()
+	}
+	writeJSON(w, http.StatusOK, applications)
+}
+
+// validateJobSeries checks the series like a Job, taking its first
+// occurrence, and then its rule and time zone. It returns a
+// *domain.ValidationError listing every problem, or nil when the series is
+// valid.
+func validateJobSeries(s models.JobSeries) error {
+	var v domain.ValidationError
+	var invalid *domain.ValidationError
+
+	err := validateJob(models.Job{
+		Description: s.Description,
+		Dog:         s.Dog,
+		Activities:  s.Activities,
+		StartsAt:    s.StartsAt,
+		EndsAt:      s.EndsAt,
+	})
+	if errors.As(err, &invalid) {
+		v.Violations = append(v.Violations, invalid.Violations...)
+	}
+
+	_, err = series.NewRecurrence(s)
+	if errors.As(err, &invalid) {
+		v.Violations = append(v.Violations, invalid.Violations...)
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (h *Handler) GetJobs(w http.ResponseWriter, r *http.Request, params models.GetJobsParams) {
+	query := domain.JobQuery{
+		DogSize:       params.DogSize,
+		StartsAfter:   params.StartsAfter,
+		EndsBefore:    params.EndsBefore,
+		Open:          params.Open,
+		CreatorUserId: params.CreatorUserId,
+		SeriesId:      params.SeriesId,
+		Limit:         h.pagination.limit(params.Limit),
+	}
+	if params.Activity != nil {
+		query.Activities = *params.Activity
+	}
+	if params.Offset != nil {
+		query.Offset = *params.Offset
+	}
+	if params.Sort != nil {
+		query.SortBy = *params.Sort
+	}
+	if params.Order != nil {
+		query.Descending = *params.Order == models.Desc
+	}
+
+	if err := validateJobQuery(query); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	if params.FitsSchedule != nil && *params.FitsSchedule {
+		// A job that has started is no longer one to take on, and leaving
+		// those out keeps the sitter's past jobs out of the busy query.
+		if query.StartsAfter == nil {
+			now := time.Now()
+			query.StartsAfter = &now
+		}
+		principal, _ := auth.PrincipalFromContext(r.Context())
+		busy, err := h.busy(r.Context(), principal.UserId, query)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+		query.Avoid = busy
+	}
+
+	jobs, total, err := h.jobRepository.GetJobs(r.Context(), query)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	hasMore := query.Offset+len(jobs) < total
+	writeJSON(w, http.StatusOK, models.InlineResponse200{
+		Items:      &jobs,
+		TotalItems: &total,
+		HasMore:    &hasMore,
+	})
+}
+
+// busy returns the times, padded by the scheduling buffer, that the jobs
+// userId works take up. Only the jobs within the buffer of the span query
+// covers are looked at.
+func (h *Handler) busy(ctx context.Context, userId string, query domain.JobQuery) ([]domain.Interval, error) {
+	working := domain.JobQuery{WorkerUserId: &userId}
+	if query.StartsAfter != nil {
+		endsAfter := query.StartsAfter.Add(-h.schedule.Buffer)
+		working.EndsAfter = &endsAfter
+	}
+	if query.EndsBefore != nil {
+		startsBefore := query.EndsBefore.Add(h.schedule.Buffer)
+		working.StartsBefore = &startsBefore
+	}
+
+	jobs, _, err := h.jobRepository.GetJobs(ctx, working)
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make([]domain.Interval, 0, len(jobs))
+	for _, job := range jobs {
+		busy = append(busy, domain.Interval{Start: job.StartsAt, End: job.EndsAt}.Pad(h.schedule.Buffer))
+	}
+
+	return busy, nil
+}
+
+// GetJobsForUser lists the jobs a PetOwner has posted and the jobs a
+// PetSitter is working on, soonest first. Users with both roles, or with
+// neither, get every job they take part in unless role narrows it down.
+func (h *Handler) GetJobsForUser(w http.ResponseWriter, r *http.Request, id string, params models.GetJobsForUserParams) {
+	user, err := h.userRepository.GetUsersId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("user not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	query, ok := jobsForUserQuery(user, params.Role)
+	if !ok {
+		problem.Write(w, r, problem.InvalidField(models.Query, "role", "must be PetOwner or PetSitter"))
+		return
+	}
+	query.Limit = h.pagination.limit(params.Limit)
+	if params.Offset != nil {
+		query.Offset = *params.Offset
+	}
+	if params.Status != nil {
+		var open bool
+		switch *params.Status {
+		case models.Open:
+			open = true
+		case models.Filled:
+		default:
+			problem.Write(w, r, problem.InvalidField(models.Query, "status", "unknown status "+string(*params.Status)))
+			return
+		}
+		query.Open = &open
+	}
+
+	if err := validateJobQuery(query); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	jobs, total, err := h.jobRepository.GetJobs(r.Context(), query)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	hasMore := query.Offset+len(jobs) < total
+	writeJSON(w, http.StatusOK, models.InlineResponse200{
+		Items:      &jobs,
+		TotalItems: &total,
+		HasMore:    &hasMore,
+	})
+}
+
+// jobsForUserQuery selects the jobs user takes part in: those a PetOwner
+// has posted and those a PetSitter is working on, narrowed to role when it is
+// set. It reports false for a role other than PetOwner or PetSitter.
+func jobsForUserQuery(user models.User, role *models.UserRoles) (domain.JobQuery, bool) {
+	owner := slices.Contains(user.Roles, models.PetOwner)
+	sitter := slices.Contains(user.Roles, models.PetSitter)
+	if role != nil {
+		switch *role {
+		case models.PetOwner:
+			owner, sitter = true, false
+		case models.PetSitter:
+			owner, sitter = false, true
+		default:
+			return domain.JobQuery{}, false
+		}
+	}
+
+	var query domain.JobQuery
+	switch {
+	case owner && !sitter:
+		query.CreatorUserId = user.Id
+	case sitter && !owner:
+		query.WorkerUserId = user.Id
+	default:
+		query.ParticipantUserId = user.Id
+	}
+
+	return query, true
+}
+
+func (h *Handler) PostJobs(w http.ResponseWriter, r *http.Request) {
+	var body models.PostJobsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	if err := validateJob(body); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	if err := h.snapshotPets(r.Context(), &body, principal.UserId); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	body.CreatorUserId = &principal.UserId
+	body.SeriesId, body.RecurrenceId = nil, nil
+
+	job, err := h.jobRepository.PostJobs(r.Context(), body)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	h.metrics.JobPosted()
+	trace.SpanFromContext(r.Context()).SetAttributes(tracing.JobIDKey.String(*job.Id))
+	w.Header().Set("Location", "/jobs/"+*job.Id)
+	writeJSON(w, http.StatusCreated, job)
+}
+
+// DeleteJobsId removes a job that has not been filled, together with all of
+// its applications. A job with a worker is kept and 409 Conflict is returned;
+// the accepted application has to be withdrawn first. A deleted occurrence of
+// a series becomes an exception of the series.
+func (h *Handler) DeleteJobsId(w http.ResponseWriter, r *http.Request, id string) {
+	job, err := h.jobRepository.GetJobsId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	err = h.jobApplicationRepository.DeleteJob(r.Context(), id, func(ctx context.Context) error {
+		return h.series.Skip(ctx, job)
+	})
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job not found"))
+		return
+	}
+	if errors.Is(err, domain.ErrConflict) {
+		problem.Write(w, r, problem.Conflict("job has an accepted application and cannot be deleted"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetJobsId(w http.ResponseWriter, r *http.Request, id string) {
+	job, err := h.jobRepository.GetJobsId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// PutJobsId replaces the editable fields of a job. Read-only fields in the
+// body, such as worker_user_id and creator_user_id, are ignored. The pets
+// the job names are snapshotted again. A job with a worker can only be moved
+// to where it keeps clear of the worker's other jobs.
+func (h *Handler) PutJobsId(w http.ResponseWriter, r *http.Request, id string) {
+	var body models.PutJobsIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	if err := validateJob(body); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	current, err := h.jobRepository.GetJobsId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	if err := h.snapshotPets(r.Context(), &body, *current.CreatorUserId); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	job, err := h.jobRepository.PutJobsId(r.Context(), id, body, h.schedule.Buffer)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("job not found"))
+		return
+	}
+	if errors.Is(err, domain.ErrConflict) {
+		problem.Write(w, r, problem.Conflict(err.Error()))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// validateJobQuery returns a *domain.ValidationError listing every invalid
+// parameter of query, or nil when the query is valid.
+func validateJobQuery(query domain.JobQuery) error {
+	var v domain.ValidationError
+
+	if query.Limit < 1 {
+		v.Add("limit", "must be at least 1")
+	}
+	if query.Offset < 0 {
+		v.Add("offset", "must not be negative")
+	}
+	for _, activity := range query.Activities {
+		if !validActivity(activity) {
+			v.Add("activity", "unknown activity "+string(activity))
+		}
+	}
+	if query.DogSize != nil && !validDogSize(*query.DogSize) {
+		v.Add("dog_size", "unknown dog size "+string(*query.DogSize))
+	}
+	switch query.SortBy {
+	case "", models.StartsAt, models.CreatedAt:
+	default:
+		v.Add("sort", "unknown sort field "+string(query.SortBy))
+	}
+	if query.StartsAfter != nil && query.EndsBefore != nil && !query.EndsBefore.After(*query.StartsAfter) {
+		v.Add("ends_before", "must be after starts_after")
+	}
+
+	return v.Err()
+}
+
+// validateJob checks the fields the spec marks as required on Job. It returns
+// a *domain.ValidationError listing every problem, or nil when the job is
+// valid.
+func validateJob(job models.Job) error {
+	var v domain.ValidationError
+
+	if job.Description == "" {
+		v.Add("description", "is required")
+	}
+	if len(job.Activities) == 0 {
+		v.Add("activities", "must contain at least one activity")
+	}
+	for _, activity := range job.Activities {
+		if !validActivity(activity) {
+			v.Add("activities", "unknown activity "+string(activity))
+		}
+	}
+	if job.StartsAt.IsZero() {
+		v.Add("starts_at", "is required")
+	}
+	if job.EndsAt.IsZero() {
+		v.Add("ends_at", "is required")
+	} else if !job.EndsAt.After(job.StartsAt) {
+		v.Add("ends_at", "must be after starts_at")
+	}
+	if job.Dog != nil {
+		if job.Dog.Breed == "" {
+			v.Add("dog.breed", "is required")
+		}
+		if !validDogSize(job.Dog.Size) {
+			v.Add("dog.size", "unknown dog size "+string(job.Dog.Size))
+		}
+		if job.Dog.YearsOld < 0 {
+			v.Add("dog.years_old", "must not be negative")
+		}
+	}
+	if job.PetIds != nil {
+		if len(*job.PetIds) == 0 {
+			v.Add("pet_ids", "must contain at least one pet")
+		}
+		if job.Dog != nil {
+			v.Add("dog", "must not be set together with pet_ids")
+		}
+		seen := make(map[string]bool)
+		for _, petId := range *job.PetIds {
+			if seen[petId] {
+				v.Add("pet_ids", "lists "+petId+" twice")
+			}
+			seen[petId] = true
+		}
+	}
+
+	return v.Err()
+}
+
+func validActivity(activity models.JobActivities) bool {
+	switch activity {
+	case models.Walk, models.Dropin, models.Boarding, models.Sitting, models.Daycare:
+		return true
+	}
+
+	return false
+}
+
+func validDogSize(size models.JobDogSize) bool {
+	switch size {
+	case models.Small, models.Medium, models.Large:
+		return true
+	}
+
+	return false
+}
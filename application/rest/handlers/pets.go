@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func (h *Handler) GetUserPets(w http.ResponseWriter, r *http.Request, id string) {
+	_, err := h.userRepository.GetUsersId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("user not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	pets, err := h.petRepository.GetPets(r.Context(), id)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	now := time.Now()
+	for i := range pets {
+		pets[i] = withAge(pets[i], now)
+	}
+
+	writeJSON(w, http.StatusOK, pets)
+}
+
+// PostUserPet adds a pet to a PetOwner. Other users have no pets and get
+// 409 Conflict.
+func (h *Handler) PostUserPet(w http.ResponseWriter, r *http.Request, id string) {
+	var body models.PostUserPetJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	now := time.Now()
+	if err := validatePet(body, now); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	user, err := h.userRepository.GetUsersId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("user not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	if !slices.Contains(user.Roles, models.PetOwner) {
+		problem.Write(w, r, problem.Conflict("only PetOwner users have pets"))
+		return
+	}
+
+	body.OwnerUserId = &id
+	pet, err := h.petRepository.PostPet(r.Context(), body)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/users/"+id+"/pets/"+*pet.Id)
+	writeJSON(w, http.StatusCreated, withAge(pet, now))
+}
+
+func (h *Handler) GetUserPet(w http.ResponseWriter, r *http.Request, id string, petId string) {
+	pet, ok := h.userPet(w, r, id, petId)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, withAge(pet, time.Now()))
+}
+
+// PutUserPet replaces the editable fields of a pet. Jobs keep the snapshot
+// of the pet they were saved with.
+func (h *Handler) PutUserPet(w http.ResponseWriter, r *http.Request, id string, petId string) {
+	var body models.PutUserPetJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	now := time.Now()
+	if err := validatePet(body, now); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	if _, ok := h.userPet(w, r, id, petId); !ok {
+		return
+	}
+
+	pet, err := h.petRepository.PutPet(r.Context(), petId, body)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("pet not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, withAge(pet, now))
+}
+
+func (h *Handler) DeleteUserPet(w http.ResponseWriter, r *http.Request, id string, petId string) {
+	if _, ok := h.userPet(w, r, id, petId); !ok {
+		return
+	}
+
+	err := h.petRepository.DeletePet(r.Context(), petId)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userPet returns the pet petId of the user id. A pet of another user is
+// reported as not found, like a missing one. When it reports false it has
+// written the problem to w.
+func (h *Handler) userPet(w http.ResponseWriter, r *http.Request, id string, petId string) (models.Pet, bool) {
+	pet, err := h.petRepository.GetPet(r.Context(), petId)
+	if errors.Is(err, domain.ErrNotFound) || err == nil && *pet.OwnerUserId != id {
+		problem.Write(w, r, problem.NotFound("pet not found"))
+		return models.Pet{}, false
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return models.Pet{}, false
+	}
+
+	return pet, true
+}
+
+// snapshotPets fills in the dogs of job. A job naming pet ids gets a
+// snapshot of each of them, which must belong to ownerUserId, and dog is the
+// first one; the ages are those at the start of the job. A job with an
+// inline dog gets it as its only dog. It returns a *domain.ValidationError
+// naming the pets that could not be used.
+func (h *Handler) snapshotPets(ctx context.Context, job *models.Job, ownerUserId string) error {
+	job.Dogs = nil
+	if job.PetIds == nil {
+		if job.Dog != nil {
+			job.Dogs = &[]models.JobDog{*job.Dog}
+		}
+		return nil
+	}
+
+	var v domain.ValidationError
+	dogs := make([]models.JobDog, 0, len(*job.PetIds))
+	for _, petId := range *job.PetIds {
+		pet, err := h.petRepository.GetPet(ctx, petId)
+		if errors.Is(err, domain.ErrNotFound) || err == nil && *pet.OwnerUserId != ownerUserId {
+			v.Add("pet_ids", "unknown pet "+petId)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		dogs = append(dogs, petSnapshot(pet, job.StartsAt))
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	dog := dogs[0]
+	job.Dog = &dog
+	job.Dogs = &dogs
+
+	return nil
+}
+
+// petSnapshot returns pet as a job's dog, aged as of at.
+func petSnapshot(pet models.Pet, at time.Time) models.JobDog {
+	name := pet.Name
+	return models.JobDog{
+		Name:     &name,
+		Breed:    pet.Breed,
+		Size:     pet.Size,
+		YearsOld: yearsOld(pet.BirthDate, at),
+	}
+}
+
+// withAge returns pet with its age as of now.
+func withAge(pet models.Pet, now time.Time) models.Pet {
+	years := yearsOld(pet.BirthDate, now)
+	pet.YearsOld = &years
+	return pet
+}
+
+// yearsOld returns the number of birthdays since birthDate on the UTC date
+// of at. Someone born on 29 February has their birthday on 1 March in other
+// years.
+func yearsOld(birthDate openapi_types.Date, at time.Time) int {
+	at = at.UTC()
+	years := at.Year() - birthDate.Year()
+	if at.Month() < birthDate.Month() || at.Month() == birthDate.Month() && at.Day() < birthDate.Day() {
+		years--
+	}
+	if years < 0 {
+		return 0
+	}
+
+	return years
+}
+
+// validatePet checks the fields of pet, with now deciding what is in the
+// future. It returns a *domain.ValidationError listing every problem, or nil
+// when the pet is valid.
+func validatePet(pet models.Pet, now time.Time) error {
+	var v domain.ValidationError
+
+	if pet.Name == "" {
+		v.Add("name", "is required")
+	}
+	if pet.Breed == "" {
+		v.Add("breed", "is required")
+	}
+	if !validDogSize(pet.Size) {
+		v.Add("size", "unknown dog size "+string(pet.Size))
+	}
+	if pet.BirthDate.IsZero() {
+		v.Add("birth_date", "is required")
+	} else if pet.BirthDate.After(now) {
+		v.Add("birth_date", "must not be in the future")
+	}
+	if pet.Temperament != nil {
+		seen := make(map[models.PetTemperament]bool)
+		for _, flag := range *pet.Temperament {
+			switch {
+			case !validTemperament(flag):
+				v.Add("temperament", "unknown temperament "+string(flag))
+			case seen[flag]:
+				v.Add("temperament", "lists "+string(flag)+" twice")
+			}
+			seen[flag] = true
+		}
+	}
+
+	return v.Err()
+}
+
+func validTemperament(flag models.PetTemperament) bool {
+	switch flag {
+	case models.GoodWithDogs, models.GoodWithCats, models.GoodWithChildren,
+		models.Anxious, models.Reactive, models.EscapeArtist, models.NeedsMuzzle:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func date(year int, month time.Month, day int) openapi_types.Date {
+	return openapi_types.Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestYearsOld(t *testing.T) {
+	newYork := time.FixedZone("EST", -5*60*60)
+
+	tests := []struct {
+		name      string
+		birthDate openapi_types.Date
+		at        time.Time
+		expected  int
+	}{
+		{name: "born today", birthDate: date(2024, 3, 4), at: time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC), expected: 0},
+		{name: "day before the birthday", birthDate: date(2020, 3, 4), at: time.Date(2024, 3, 3, 23, 59, 0, 0, time.UTC), expected: 3},
+		{name: "on the birthday", birthDate: date(2020, 3, 4), at: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), expected: 4},
+		{name: "month before the birthday", birthDate: date(2020, 3, 4), at: time.Date(2024, 2, 28, 12, 0, 0, 0, time.UTC), expected: 3},
+		{name: "month after the birthday", birthDate: date(2020, 3, 4), at: time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC), expected: 4},
+		{name: "leap day on 28 February", birthDate: date(2020, 2, 29), at: time.Date(2023, 2, 28, 12, 0, 0, 0, time.UTC), expected: 2},
+		{name: "leap day on 1 March", birthDate: date(2020, 2, 29), at: time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC), expected: 3},
+		{name: "leap day in a leap year", birthDate: date(2020, 2, 29), at: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), expected: 4},
+		{name: "counts the UTC date", birthDate: date(2020, 3, 5), at: time.Date(2024, 3, 4, 20, 0, 0, 0, newYork), expected: 4},
+		{name: "born in the future", birthDate: date(2025, 1, 1), at: time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := yearsOld(tt.birthDate, tt.at); got != tt.expected {
+				t.Fatalf("expected %d years old, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidatePet(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	valid := models.Pet{
+		Name:      "Rex",
+		Breed:     "Beagle",
+		Size:      models.Small,
+		BirthDate: date(2021, 6, 1),
+	}
+	with := func(change func(pet *models.Pet)) models.Pet {
+		pet := valid
+		change(&pet)
+		return pet
+	}
+	temperament := func(flags ...models.PetTemperament) func(pet *models.Pet) {
+		return func(pet *models.Pet) { pet.Temperament = &flags }
+	}
+
+	tests := []struct {
+		name    string
+		pet     models.Pet
+		fields  []string
+		reasons []string
+	}{
+		{name: "valid", pet: valid},
+		{name: "born today", pet: with(func(pet *models.Pet) { pet.BirthDate = date(2024, 3, 4) })},
+		{
+			name:    "born tomorrow",
+			pet:     with(func(pet *models.Pet) { pet.BirthDate = date(2024, 3, 5) }),
+			fields:  []string{"birth_date"},
+			reasons: []string{"must not be in the future"},
+		},
+		{
+			name:    "every required field missing",
+			pet:     models.Pet{},
+			fields:  []string{"name", "breed", "size", "birth_date"},
+			reasons: []string{"is required", "is required", "unknown dog size ", "is required"},
+		},
+		{
+			name:    "unknown size",
+			pet:     with(func(pet *models.Pet) { pet.Size = "huge" }),
+			fields:  []string{"size"},
+			reasons: []string{"unknown dog size huge"},
+		},
+		{name: "temperament", pet: with(temperament(models.GoodWithDogs, models.Anxious, models.NeedsMuzzle))},
+		{name: "no temperament", pet: with(temperament())},
+		{
+			name:    "unknown temperament",
+			pet:     with(temperament(models.GoodWithDogs, "grumpy")),
+			fields:  []string{"temperament"},
+			reasons: []string{"unknown temperament grumpy"},
+		},
+		{
+			name:    "temperament listed twice",
+			pet:     with(temperament(models.Reactive, models.Anxious, models.Reactive, models.Reactive)),
+			fields:  []string{"temperament", "temperament"},
+			reasons: []string{"lists reactive twice", "lists reactive twice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePet(tt.pet, now)
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validation *domain.ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("expected a *domain.ValidationError, got %v", err)
+			}
+			if len(validation.Violations) != len(tt.fields) {
+				t.Fatalf("expected violations of %v, got %+v", tt.fields, validation.Violations)
+			}
+			for i, v := range validation.Violations {
+				if v.Field != tt.fields[i] || v.Reason != tt.reasons[i] {
+					t.Fatalf("expected %s %s, got %s %s", tt.fields[i], tt.reasons[i], v.Field, v.Reason)
+				}
+			}
+		})
+	}
+}
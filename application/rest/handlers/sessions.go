@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/logging"
+)
+
+func (h *Handler) StartSession(w http.ResponseWriter, r *http.Request) {
+	var body models.StartSessionJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+	if body.Email == nil || body.Password == nil {
+		problem.Write(w, r, problem.BadRequest("email and password are required"))
+		return
+	}
+
+	// Lock out by email rather than by client, so that guessing one
+	// account's password from many addresses is slowed down too. The
+	// attempt counts as a failure until it succeeds.
+	lockoutKey := "login:" + strings.ToLower(*body.Email)
+	if err := h.lockout.Attempt(r.Context(), lockoutKey); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	user, err := h.credentials.Authenticate(r.Context(), *body.Email, *body.Password)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	if err := h.lockout.Succeeded(r.Context(), lockoutKey); err != nil {
+		logging.FromContext(r.Context()).Error("Clearing failed logins", slog.Any("error", err))
+	}
+
+	session, err := h.sessions.Start(r.Context(), user)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, session)
+}
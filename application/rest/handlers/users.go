@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/logging"
+)
+
+func (h *Handler) PostUsers(w http.ResponseWriter, r *http.Request) {
+	var body models.PostUsersJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	if err := validateUser(body, true); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	if !canGrantRoles(r, body.Roles) {
+		problem.Write(w, r, problem.Forbidden("only admins can grant the Admin role"))
+		return
+	}
+
+	hash, err := h.hashPassword(*body.Password)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	user, err := h.userRepository.PostUsers(r.Context(), body, hash)
+	if errors.Is(err, domain.ErrConflict) {
+		problem.Write(w, r, problem.Conflict("a user with this email already exists"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/users/"+*user.Id)
+	writeJSON(w, http.StatusCreated, user)
+}
+
+func (h *Handler) DeleteUsersId(w http.ResponseWriter, r *http.Request, id string) {
+	err := h.userRepository.DeleteUsersId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("user not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	if err := h.sessions.EndAll(r.Context(), id); err != nil {
+		logging.FromContext(r.Context()).Error("Ending the sessions of a deleted user", slog.String("user_id", id), slog.Any("error", err))
+	}
+	err = h.availabilityRepository.DeleteAvailability(r.Context(), id)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		logging.FromContext(r.Context()).Error("Deleting the availability of a deleted user", slog.String("user_id", id), slog.Any("error", err))
+	}
+	err = h.calendar.Revoke(r.Context(), id)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		logging.FromContext(r.Context()).Error("Revoking the calendar feed of a deleted user", slog.String("user_id", id), slog.Any("error", err))
+	}
+	if err := h.petRepository.DeletePetsByOwner(r.Context(), id); err != nil {
+		logging.FromContext(r.Context()).Error("Deleting the pets of a deleted user", slog.String("user_id", id), slog.Any("error", err))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetUsersId(w http.ResponseWriter, r *http.Request, id string) {
+	user, err := h.userRepository.GetUsersId(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("user not found"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (h *Handler) PutUsersId(w http.ResponseWriter, r *http.Request, id string) {
+	var body models.PutUsersIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problem.Write(w, r, problem.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	if err := validateUser(body, false); err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	if !canGrantRoles(r, body.Roles) {
+		problem.Write(w, r, problem.Forbidden("only admins can grant the Admin role"))
+		return
+	}
+
+	var hash string
+	if body.Password != nil {
+		var err error
+		if hash, err = h.hashPassword(*body.Password); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+	}
+
+	user, err := h.userRepository.PutUsersId(r.Context(), id, body, hash)
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(w, r, problem.NotFound("user not found"))
+		return
+	}
+	if errors.Is(err, domain.ErrConflict) {
+		problem.Write(w, r, problem.Conflict("a user with this email already exists"))
+		return
+	}
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+// hashPassword enforces the password policy and hashes password. Policy
+// violations are returned as a *auth.PolicyError.
+func (h *Handler) hashPassword(password string) (string, error) {
+	if err := h.credentials.CheckPolicy(password); err != nil {
+		return "", err
+	}
+
+	return h.credentials.Hash(password)
+}
+
+// canGrantRoles reports whether the caller of r may give a user roles. Only
+// admins may hand out the Admin role.
+func canGrantRoles(r *http.Request, roles []models.UserRoles) bool {
+	if !slices.Contains(roles, models.Admin) {
+		return true
+	}
+
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	return ok && principal.HasRole(models.Admin)
+}
+
+// validateUser checks the fields the spec marks as required on User, and the
+// password when requirePassword is set. It returns a *domain.ValidationError
+// listing every problem, or nil when the user is valid.
+func validateUser(user models.User, requirePassword bool) error {
+	var v domain.ValidationError
+
+	if user.Email == "" {
+		v.Add("email", "is required")
+	}
+	if user.FullName == "" {
+		v.Add("full_name", "is required")
+	}
+	if requirePassword && user.Password == nil {
+		v.Add("password", "is required")
+	}
+	if len(user.Roles) == 0 {
+		v.Add("roles", "must contain at least one role")
+	}
+	for _, role := range user.Roles {
+		switch role {
+		case models.PetOwner, models.PetSitter, models.Admin:
+		default:
+			v.Add("roles", "unknown role "+string(role))
+		}
+	}
+
+	return v.Err()
+}
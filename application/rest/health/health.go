@@ -0,0 +1,145 @@
+// Package health answers the orchestrator's probes. /healthz reports that the
+// process is up; /readyz runs the registered dependency checks and reports
+// whether the service should receive traffic.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// ErrShuttingDown is reported by /readyz once Drain has been called.
+var ErrShuttingDown = errors.New("shutting down")
+
+// Check reports whether a dependency is usable. It must return once ctx is
+// done.
+type Check func(ctx context.Context) error
+
+type check struct {
+	name    string
+	timeout time.Duration
+	check   Check
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report is the body of both probes.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type Checker struct {
+	mu       sync.RWMutex
+	checks   []check
+	draining atomic.Bool
+}
+
+func New() *Checker {
+	return &Checker{}
+}
+
+// Register adds a readiness check. Each run of fn gets at most timeout.
+func (c *Checker) Register(name string, timeout time.Duration, fn Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, check{name: name, timeout: timeout, check: fn})
+}
+
+// Drain makes /readyz report the service as unavailable from now on, so that
+// load balancers stop sending traffic before the listeners close.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Live answers /healthz. It does not look at dependencies: a failing database
+// is no reason to restart the process.
+func (c *Checker) Live(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, http.StatusOK, Report{Status: StatusOK})
+}
+
+// Ready answers /readyz with the result of every check, run concurrently.
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	if c.draining.Load() {
+		writeReport(w, http.StatusServiceUnavailable, Report{
+			Status: StatusUnavailable,
+			Checks: map[string]Result{"shutdown": {Status: StatusUnavailable, Error: ErrShuttingDown.Error()}},
+		})
+		return
+	}
+
+	report := c.Run(r.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeReport(w, status, report)
+}
+
+// Run runs every registered check and reports the service ready when all of
+// them pass.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]check(nil), c.checks...)
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+	for i, ch := range checks {
+		wg.Add(1)
+		go func(i int, ch check) {
+			defer wg.Done()
+			results[i] = ch.run(ctx)
+		}(i, ch)
+	}
+	wg.Wait()
+
+	for i, ch := range checks {
+		report.Checks[ch.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+
+	return report
+}
+
+func (ch check) run(ctx context.Context) Result {
+	ctx, cancel := context.WithTimeout(ctx, ch.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ch.check(ctx)
+	result := Result{Status: StatusOK, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func writeReport(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,196 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func pass(ctx context.Context) error {
+	return nil
+}
+
+// hang blocks until the check's context is done.
+func hang(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func fail(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func serve(t *testing.T, handler http.HandlerFunc) (int, Report) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("expected Cache-Control no-store, got %q", rec.Header().Get("Cache-Control"))
+	}
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rec.Code, report
+}
+
+func TestReady(t *testing.T) {
+	type registered struct {
+		name    string
+		timeout time.Duration
+		check   Check
+	}
+
+	tests := []struct {
+		name    string
+		checks  []registered
+		drain   bool
+		status  int
+		failing map[string]string
+		passing []string
+	}{
+		{name: "no checks", status: http.StatusOK},
+		{
+			name:    "every check passes",
+			checks:  []registered{{"mongo", time.Second, pass}, {"cache", time.Second, pass}},
+			status:  http.StatusOK,
+			passing: []string{"mongo", "cache"},
+		},
+		{
+			name:    "a check fails",
+			checks:  []registered{{"mongo", time.Second, fail}, {"cache", time.Second, pass}},
+			status:  http.StatusServiceUnavailable,
+			failing: map[string]string{"mongo": "connection refused"},
+			passing: []string{"cache"},
+		},
+		{
+			name:    "a check times out",
+			checks:  []registered{{"mongo", 20 * time.Millisecond, hang}, {"cache", time.Second, pass}},
+			status:  http.StatusServiceUnavailable,
+			failing: map[string]string{"mongo": context.DeadlineExceeded.Error()},
+			passing: []string{"cache"},
+		},
+		{
+			name:    "draining",
+			checks:  []registered{{"mongo", time.Second, pass}},
+			drain:   true,
+			status:  http.StatusServiceUnavailable,
+			failing: map[string]string{"shutdown": ErrShuttingDown.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			for _, r := range tt.checks {
+				c.Register(r.name, r.timeout, r.check)
+			}
+			if tt.drain {
+				c.Drain()
+			}
+
+			status, report := serve(t, c.Ready)
+			if status != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, status)
+			}
+			expected := StatusOK
+			if tt.status != http.StatusOK {
+				expected = StatusUnavailable
+			}
+			if report.Status != expected {
+				t.Fatalf("expected report status %s, got %s", expected, report.Status)
+			}
+			if len(report.Checks) != len(tt.failing)+len(tt.passing) {
+				t.Fatalf("expected %d checks, got %+v", len(tt.failing)+len(tt.passing), report.Checks)
+			}
+			for name, reason := range tt.failing {
+				if result := report.Checks[name]; result.Status != StatusUnavailable || result.Error != reason {
+					t.Fatalf("expected %s to fail with %q, got %+v", name, reason, result)
+				}
+			}
+			for _, name := range tt.passing {
+				if result := report.Checks[name]; result.Status != StatusOK || result.Error != "" {
+					t.Fatalf("expected %s to pass, got %+v", name, result)
+				}
+			}
+		})
+	}
+}
+
+func TestLiveWhileDraining(t *testing.T) {
+	c := New()
+	c.Register("mongo", time.Second, fail)
+	c.Drain()
+
+	status, report := serve(t, c.Live)
+	if status != http.StatusOK || report.Status != StatusOK {
+		t.Fatalf("expected a live process, got %d %+v", status, report)
+	}
+}
+
+func TestRunChecksConcurrently(t *testing.T) {
+	const checks = 5
+	c := New()
+
+	// Every check waits for all of them to have started, so the run only
+	// completes when they run at the same time.
+	var started sync.WaitGroup
+	started.Add(checks)
+	all := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(all)
+	}()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		c.Register(name, time.Second, func(ctx context.Context) error {
+			started.Done()
+			select {
+			case <-all:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+
+	report := c.Run(context.Background())
+	if report.Status != StatusOK || len(report.Checks) != checks {
+		t.Fatalf("expected %d passing checks, got %+v", checks, report)
+	}
+}
+
+func TestConcurrentRuns(t *testing.T) {
+	c := New()
+	c.Register("mongo", time.Second, pass)
+	c.Register("cache", time.Second, fail)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rec := httptest.NewRecorder()
+			c.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			var report Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if rec.Code != http.StatusServiceUnavailable || report.Checks["cache"].Status != StatusUnavailable || report.Checks["mongo"].Status != StatusOK {
+				t.Errorf("unexpected report %d %+v", rec.Code, report)
+			}
+		}()
+		// Registering while probes run must be safe too.
+		c.Register("late", time.Second, pass)
+	}
+	wg.Wait()
+}
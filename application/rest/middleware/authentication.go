@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/application/rest/server"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/logging"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Authenticate rejects requests to operations protected by the SessionToken
+// scheme unless they carry a valid session token. The generated wrapper only
+// sets models.SessionTokenScopes for protected operations, so operations
+// declared with `security: []` pass through untouched.
+func Authenticate(sessions *auth.Sessions) server.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Context().Value(models.SessionTokenScopes) == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				problem.Write(w, r, problem.Unauthenticated("missing session token"))
+				return
+			}
+
+			principal, err := sessions.Validate(r.Context(), header)
+			if err != nil {
+				problem.Error(w, r, err)
+				return
+			}
+
+			// Tag the request's logger, and so its access log, and its span
+			// with the user.
+			logging.AddAttrs(r.Context(), slog.String("user_id", principal.UserId))
+			trace.SpanFromContext(r.Context()).SetAttributes(semconv.EnduserID(principal.UserId))
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/infrastructure/repositories/memory"
+	"github.com/gorilla/mux"
+)
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions, err := auth.NewSessions("a-test-secret-that-is-long-enough", time.Hour, memory.NewSessionRepository(), users)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	forger, err := auth.NewSessions("another-secret-that-is-long-enough", time.Hour, memory.NewSessionRepository(), users)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	user, err := users.PostUsers(ctx, models.User{Email: "owner@example.com", FullName: "Olga Owner", Roles: []models.UserRoles{models.PetOwner}}, "hash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	session, err := sessions.Start(ctx, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	forged, err := forger.Start(ctx, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// post_jobs is protected and requires the pet_owner role.
+	scoped := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), models.SessionTokenScopes, []string{})))
+		})
+	}
+	created := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.PrincipalFromContext(r.Context())
+		if !ok || principal.UserId != *user.Id {
+			t.Errorf("expected the principal of %s, got %+v", *user.Id, principal)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	router := mux.NewRouter()
+	router.Handle("/api/jobs", scoped(Authenticate(sessions)(Authorize(auth.NewAuthorizer(auth.Rules))(created)))).Methods(http.MethodPost)
+	router.Handle("/api/health", Authenticate(sessions)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))).Methods(http.MethodGet)
+
+	setRoles := func(roles ...models.UserRoles) func() {
+		return func() {
+			user.Roles = roles
+			if _, err := users.PutUsersId(ctx, *user.Id, user, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	// Every step runs against the state the previous ones left behind.
+	tests := []struct {
+		name     string
+		before   func()
+		method   string
+		path     string
+		header   string
+		expected int
+	}{
+		{name: "valid token", method: http.MethodPost, path: "/api/jobs", header: *session.AuthHeader, expected: http.StatusCreated},
+		{name: "unprotected operation", method: http.MethodGet, path: "/api/health", expected: http.StatusOK},
+		{name: "missing token", method: http.MethodPost, path: "/api/jobs", expected: http.StatusUnauthorized},
+		{name: "forged signature", method: http.MethodPost, path: "/api/jobs", header: *forged.AuthHeader, expected: http.StatusUnauthorized},
+		{name: "role removed", before: setRoles(models.PetSitter), method: http.MethodPost, path: "/api/jobs", header: *session.AuthHeader, expected: http.StatusForbidden},
+		{name: "role granted again", before: setRoles(models.PetSitter, models.PetOwner), method: http.MethodPost, path: "/api/jobs", header: *session.AuthHeader, expected: http.StatusCreated},
+		{
+			name: "sessions ended",
+			before: func() {
+				if err := sessions.EndAll(ctx, *user.Id); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+			method:   http.MethodPost,
+			path:     "/api/jobs",
+			header:   *session.AuthHeader,
+			expected: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.before != nil {
+			tt.before()
+		}
+
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.expected {
+			t.Fatalf("%s: expected status %d, got %d: %s", tt.name, tt.expected, rec.Code, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/application/rest/server"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/gorilla/mux"
+)
+
+// Authorize enforces auth.Rules on operations protected by the SessionToken
+// scheme. It must run after Authenticate.
+func Authorize(authorizer *auth.Authorizer) server.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, protected := r.Context().Value(models.SessionTokenScopes).([]string)
+			if !protected {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, ok := auth.PrincipalFromContext(r.Context())
+			if !ok {
+				problem.Write(w, r, problem.Unauthenticated("missing session token"))
+				return
+			}
+
+			err := authorizer.Authorize(r.Context(), principal, OperationID(r), scopes, mux.Vars(r)["id"])
+			if err != nil {
+				// Denials and missing resources map to 403 and 404.
+				problem.Error(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// CORSOptions configures CORS.
+type CORSOptions struct {
+	// AllowedOrigins are the origins browsers may call the API from, such
+	// as https://app.example.com. "*" allows any origin, and a "*." in
+	// front of the host, as in https://*.example.com, allows its subdomains.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response.
+	MaxAge time.Duration
+}
+
+// CORS lets browsers on the allowed origins call the API. It answers
+// preflight requests for every route of router itself, allowing the methods
+// the route is registered for, since the generated routes have no OPTIONS
+// handlers. It wraps the router so that error responses, which browsers
+// otherwise hide from scripts, carry the CORS headers too.
+func CORS(opts CORSOptions, router *mux.Router) func(http.Handler) http.Handler {
+	allowHeaders := strings.Join(opts.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(opts.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Add("Vary", "Origin")
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if preflight {
+				header.Add("Vary", "Access-Control-Request-Method")
+				header.Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			if !originAllowed(origin, opts.AllowedOrigins) {
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header.Set("Access-Control-Allow-Origin", origin)
+			if opts.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if exposeHeaders != "" {
+					header.Set("Access-Control-Expose-Headers", exposeHeaders)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			methods := routeMethods(router, r, opts.AllowedMethods)
+			if len(methods) == 0 {
+				// No route serves the path; let the router say so.
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			if allowHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", allowHeaders)
+			}
+			if opts.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// routeMethods returns those of methods that router has a route for at the
+// path of r.
+func routeMethods(router *mux.Router, r *http.Request, methods []string) []string {
+	var allowed []string
+	for _, method := range methods {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+
+		var match mux.RouteMatch
+		if router.Match(probe, &match) && match.MatchErr == nil && match.Route != nil {
+			allowed = append(allowed, method)
+		}
+	}
+
+	return allowed
+}
+
+func originAllowed(origin string, allowed []string) bool {
+	if slices.Contains(allowed, "*") {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	for _, pattern := range allowed {
+		if strings.EqualFold(origin, pattern) {
+			return true
+		}
+
+		scheme, host, ok := strings.Cut(pattern, "://*.")
+		if ok && strings.EqualFold(u.Scheme, scheme) && hasSubdomainOf(u.Host, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasSubdomainOf reports whether host, which may carry a port, is a
+// subdomain of domain, which must then carry the same port.
+func hasSubdomainOf(host, domain string) bool {
+	host, domain = strings.ToLower(host), strings.ToLower(domain)
+	return strings.HasSuffix(host, "."+domain) && len(host) > len(domain)+1
+}
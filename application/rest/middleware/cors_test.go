@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestOriginAllowed(t *testing.T) {
+	allowed := []string{"https://app.example.com", "https://*.example.org", "http://*.localhost:3000"}
+
+	tests := []struct {
+		name     string
+		origin   string
+		allowed  []string
+		expected bool
+	}{
+		{name: "exact", origin: "https://app.example.com", allowed: allowed, expected: true},
+		{name: "exact ignores case", origin: "https://APP.example.com", allowed: allowed, expected: true},
+		{name: "other scheme", origin: "http://app.example.com", allowed: allowed},
+		{name: "other host", origin: "https://evil.example.com", allowed: allowed},
+		{name: "other port", origin: "https://app.example.com:8443", allowed: allowed},
+		{name: "subdomain", origin: "https://app.example.org", allowed: allowed, expected: true},
+		{name: "nested subdomain", origin: "https://a.b.example.org", allowed: allowed, expected: true},
+		{name: "subdomain ignores case", origin: "HTTPS://App.Example.ORG", allowed: allowed, expected: true},
+		{name: "wildcard excludes the domain itself", origin: "https://example.org", allowed: allowed},
+		{name: "wildcard checks the scheme", origin: "http://app.example.org", allowed: allowed},
+		{name: "suffix is not a subdomain", origin: "https://evilexample.org", allowed: allowed},
+		{name: "lookalike domain", origin: "https://example.org.evil.com", allowed: allowed},
+		{name: "empty label", origin: "https://.example.org", allowed: allowed},
+		{name: "wildcard with port", origin: "http://app.localhost:3000", allowed: allowed, expected: true},
+		{name: "wildcard with other port", origin: "http://app.localhost:4000", allowed: allowed},
+		{name: "wildcard port is required", origin: "http://app.localhost", allowed: allowed},
+		{name: "opaque origin", origin: "null", allowed: allowed},
+		{name: "any origin", origin: "https://anything.test", allowed: []string{"*"}, expected: true},
+		{name: "any origin allows opaque origins", origin: "null", allowed: []string{"*"}, expected: true},
+		{name: "nothing allowed", origin: "https://app.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := originAllowed(tt.origin, tt.allowed); got != tt.expected {
+				t.Fatalf("expected %t for %s, got %t", tt.expected, tt.origin, got)
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	router := mux.NewRouter()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router.Handle("/jobs", ok).Methods(http.MethodGet, http.MethodPost)
+	router.Handle("/jobs/{id}", ok).Methods(http.MethodGet, http.MethodPut, http.MethodDelete)
+
+	handler := CORS(CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		ExposedHeaders:   []string{"ETag"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}, router)(router)
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		origin        string
+		requestMethod string
+		status        int
+		allowOrigin   string
+		allowMethods  string
+		allowHeaders  string
+		exposeHeaders string
+		maxAge        string
+		vary          []string
+	}{
+		{
+			name:   "same origin",
+			method: http.MethodGet,
+			path:   "/jobs",
+			status: http.StatusOK,
+		},
+		{
+			name:          "allowed origin",
+			method:        http.MethodGet,
+			path:          "/jobs",
+			origin:        "https://app.example.com",
+			status:        http.StatusOK,
+			allowOrigin:   "https://app.example.com",
+			exposeHeaders: "ETag",
+			vary:          []string{"Origin"},
+		},
+		{
+			name:   "other origin still reaches the handler",
+			method: http.MethodGet,
+			path:   "/jobs",
+			origin: "https://evil.example.com",
+			status: http.StatusOK,
+			vary:   []string{"Origin"},
+		},
+		{
+			name:          "preflight allows the route's methods",
+			method:        http.MethodOptions,
+			path:          "/jobs/42",
+			origin:        "https://app.example.com",
+			requestMethod: http.MethodPut,
+			status:        http.StatusNoContent,
+			allowOrigin:   "https://app.example.com",
+			allowMethods:  "GET, PUT, DELETE",
+			allowHeaders:  "Authorization, Content-Type",
+			maxAge:        "600",
+			vary:          []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:          "preflight on another route",
+			method:        http.MethodOptions,
+			path:          "/jobs",
+			origin:        "https://app.example.com",
+			requestMethod: http.MethodPost,
+			status:        http.StatusNoContent,
+			allowOrigin:   "https://app.example.com",
+			allowMethods:  "GET, POST",
+			allowHeaders:  "Authorization, Content-Type",
+			maxAge:        "600",
+			vary:          []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:          "preflight from another origin",
+			method:        http.MethodOptions,
+			path:          "/jobs",
+			origin:        "https://evil.example.com",
+			requestMethod: http.MethodPost,
+			status:        http.StatusNoContent,
+			vary:          []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:          "preflight for an unknown path",
+			method:        http.MethodOptions,
+			path:          "/nowhere",
+			origin:        "https://app.example.com",
+			requestMethod: http.MethodGet,
+			status:        http.StatusNotFound,
+			allowOrigin:   "https://app.example.com",
+			vary:          []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:        "options without a requested method is no preflight",
+			method:      http.MethodOptions,
+			path:        "/jobs",
+			origin:      "https://app.example.com",
+			status:      http.StatusMethodNotAllowed,
+			allowOrigin: "https://app.example.com",
+			// The router answers, so the exposed headers still apply.
+			exposeHeaders: "ETag",
+			vary:          []string{"Origin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.requestMethod != "" {
+				r.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+
+			header := w.Header()
+			expectHeader(t, header, "Access-Control-Allow-Origin", tt.allowOrigin)
+			expectHeader(t, header, "Access-Control-Allow-Methods", tt.allowMethods)
+			expectHeader(t, header, "Access-Control-Allow-Headers", tt.allowHeaders)
+			expectHeader(t, header, "Access-Control-Expose-Headers", tt.exposeHeaders)
+			expectHeader(t, header, "Access-Control-Max-Age", tt.maxAge)
+			if tt.allowOrigin != "" {
+				expectHeader(t, header, "Access-Control-Allow-Credentials", "true")
+			}
+
+			vary := header.Values("Vary")
+			if len(vary) != len(tt.vary) {
+				t.Fatalf("expected Vary %v, got %v", tt.vary, vary)
+			}
+			for i := range vary {
+				if vary[i] != tt.vary[i] {
+					t.Fatalf("expected Vary %v, got %v", tt.vary, vary)
+				}
+			}
+		})
+	}
+}
+
+func expectHeader(t *testing.T, header http.Header, name, expected string) {
+	t.Helper()
+
+	if got := header.Get(name); got != expected {
+		t.Fatalf("expected %s %q, got %q", name, expected, got)
+	}
+}
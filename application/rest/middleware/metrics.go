@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/bersennaidoo/agentco/application/metrics"
+	"github.com/gorilla/mux"
+)
+
+// Metrics records every request served by router under the operationId it
+// is routed to. It wraps the router rather than running in the generated
+// wrapper so that requests rejected before a handler runs, such as malformed
+// parameters and unknown paths, are counted too.
+func Metrics(m *metrics.Metrics, router *mux.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			done := m.RequestStarted()
+
+			operation := MatchOperationID(router, r)
+			if operation == "" {
+				operation = metrics.NoOperation
+			}
+
+			sw := &statusWriter{ResponseWriter: w}
+			defer func() {
+				done(operation, methodLabel(r.Method), sw.Status())
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// methodLabel returns method when it is one of the methods HTTP defines, and
+// metrics.OtherMethod otherwise.
+func methodLabel(method string) string {
+	if slices.Contains(standardMethods, method) {
+		return method
+	}
+
+	return metrics.OtherMethod
+}
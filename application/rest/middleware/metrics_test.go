@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bersennaidoo/agentco/application/metrics"
+	"github.com/gorilla/mux"
+)
+
+func TestMetricsMethodLabel(t *testing.T) {
+	m := metrics.New()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := Metrics(m, router)(router)
+
+	for _, method := range []string{http.MethodGet, http.MethodOptions, "PURGE", "X-MADE-UP-1", "X-MADE-UP-2", "get"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/api/jobs", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	scraped := rec.Body.String()
+
+	tests := []struct {
+		name     string
+		label    string
+		expected bool
+	}{
+		{name: "standard method", label: `method="GET"`, expected: true},
+		{name: "another standard method", label: `method="OPTIONS"`, expected: true},
+		{name: "made-up methods collapse", label: `method="OTHER"`, expected: true},
+		{name: "extension method", label: `method="PURGE"`},
+		{name: "made-up method", label: `method="X-MADE-UP-1"`},
+		{name: "methods are case sensitive", label: `method="get"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if strings.Contains(scraped, tt.label) != tt.expected {
+				t.Fatalf("expected %s to be present %t in\n%s", tt.label, tt.expected, scraped)
+			}
+		})
+	}
+}
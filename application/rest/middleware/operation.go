@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bersennaidoo/agentco/application/rest/server"
+	"github.com/gorilla/mux"
+)
+
+// routeOperations maps "METHOD /path/{template}" of every operation in the
+// embedded spec to its operationId.
+var routeOperations = sync.OnceValue(func() map[string]string {
+	swagger, err := server.GetSwagger()
+	if err != nil {
+		panic("loading embedded OpenAPI spec: " + err.Error())
+	}
+
+	operations := make(map[string]string)
+	for path, item := range swagger.Paths {
+		for method, op := range item.Operations() {
+			operations[method+" "+path] = op.OperationID
+		}
+	}
+
+	return operations
+})
+
+// standardMethods are the request methods HTTP defines.
+var standardMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+}
+
+// AllowedMethods returns the methods router has a route for at the path of
+// r, for the Allow header of a 405 response.
+func AllowedMethods(router *mux.Router, r *http.Request) []string {
+	return routeMethods(router, r, standardMethods)
+}
+
+// OperationID returns the operationId of the spec operation served by r, or
+// "" when r was not routed to one.
+func OperationID(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+
+	return routeOperationID(route, r.Method)
+}
+
+// MatchOperationID is OperationID for code that runs before router has
+// routed r.
+func MatchOperationID(router *mux.Router, r *http.Request) string {
+	var match mux.RouteMatch
+	if !router.Match(r, &match) || match.Route == nil {
+		return ""
+	}
+
+	return routeOperationID(match.Route, r.Method)
+}
+
+func routeOperationID(route *mux.Route, method string) string {
+	tmpl, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+
+	operations := routeOperations()
+	if id, ok := operations[method+" "+tmpl]; ok {
+		return id
+	}
+	for key, id := range operations {
+		m, path, _ := strings.Cut(key, " ")
+		if m == method && strings.HasSuffix(tmpl, path) {
+			return id
+		}
+	}
+
+	return ""
+}
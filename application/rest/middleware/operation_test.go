@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestAllowedMethods(t *testing.T) {
+	router := mux.NewRouter()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router.Handle("/jobs", ok).Methods(http.MethodGet, http.MethodPost)
+	router.Handle("/jobs/{id}", ok).Methods(http.MethodGet)
+	router.Handle("/jobs/{id}", ok).Methods(http.MethodPut, http.MethodDelete)
+	router.Handle("/healthz", ok).Methods(http.MethodGet, http.MethodHead)
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "one route", path: "/jobs", expected: "GET, POST"},
+		{name: "routes sharing a path", path: "/jobs/42", expected: "GET, PUT, DELETE"},
+		{name: "head", path: "/healthz", expected: "GET, HEAD"},
+		{name: "no route", path: "/nowhere"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("BREW", tt.path, nil)
+			if got := strings.Join(AllowedMethods(router, r), ", "); got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strconv"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/ratelimit"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/application/rest/server"
+	"github.com/bersennaidoo/agentco/physical/logging"
+)
+
+// RateLimit counts each request against the limit of its operation, per
+// authenticated user or, for anonymous requests, per client IP. Every
+// response carries RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset;
+// requests over the limit get 429 with Retry-After. It runs after
+// Authenticate, so that signed-in users are limited by account rather than
+// by address. When the limiter's store fails, requests are let through.
+func RateLimit(limiter *ratelimit.Limiter, trustedProxies []netip.Prefix) server.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := "ip:" + ClientIP(r, trustedProxies)
+			if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+				client = "user:" + principal.UserId
+			}
+
+			result, err := limiter.Take(r.Context(), OperationID(r), client)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Rate limiter unavailable", slog.Any("error", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			setRateLimitHeaders(w, result)
+			if !result.Allowed {
+				tooManyRequests(w, r, result)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimitIP counts every request against the per-address limit before it
+// is authenticated, so that clients sending bad tokens are throttled as
+// well. Only requests over the limit get its headers; the others carry those
+// of RateLimit. When the limiter's store fails, requests are let through.
+func RateLimitIP(limiter *ratelimit.Limiter, trustedProxies []netip.Prefix) server.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := limiter.TakeIP(r.Context(), ClientIP(r, trustedProxies))
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Rate limiter unavailable", slog.Any("error", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !result.Allowed {
+				setRateLimitHeaders(w, result)
+				tooManyRequests(w, r, result)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func setRateLimitHeaders(w http.ResponseWriter, result ratelimit.Result) {
+	header := w.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit.Requests))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ratelimit.Seconds(result.Reset)))
+	header.Set("RateLimit-Policy", strconv.Itoa(result.Limit.Requests)+";w="+strconv.Itoa(ratelimit.Seconds(result.Limit.Period)))
+}
+
+func tooManyRequests(w http.ResponseWriter, r *http.Request, result ratelimit.Result) {
+	w.Header().Set("Retry-After", strconv.Itoa(ratelimit.Seconds(result.RetryAfter)))
+	problem.Write(w, r, problem.TooManyRequests("rate limit of "+result.Limit.String()+" exceeded"))
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// probePaths are the health probes, which load balancers and orchestrators
+// often send over plain HTTP and which would take a redirect for a failure.
+var probePaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// RedirectHTTPS answers every request with a permanent redirect to the same
+// URL on https, except for the health probes, which are passed to next.
+// httpsAddr is the address the HTTPS server listens on; its port is kept in
+// the redirect unless it is the default, 443.
+func RedirectHTTPS(httpsAddr string, next http.Handler) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if probePaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		} else {
+			host = strings.Trim(host, "[]")
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
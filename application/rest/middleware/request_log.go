@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/bersennaidoo/agentco/application/rest/requestid"
+	"github.com/bersennaidoo/agentco/physical/logging"
+	"github.com/gorilla/mux"
+)
+
+// maxRequestIDLength bounds the client-supplied IDs that are passed on.
+const maxRequestIDLength = 128
+
+// RequestLog gives every request an ID, taken from the X-Request-ID header
+// when the client sent a usable one, and echoes it in the response. The
+// request's context carries the ID and a logger tagged with it. Once the
+// request is served, one access log line records its operation, status,
+// latency, user and response size. It wraps the whole router, so that
+// unmatched routes get an ID and a log line too.
+func RequestLog(logger *slog.Logger, router *mux.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			id := r.Header.Get(requestid.Header)
+			if !validRequestID(id) {
+				id = requestid.New()
+			}
+			w.Header().Set(requestid.Header, id)
+
+			ctx := requestid.With(r.Context(), id)
+			ctx = logging.With(ctx, logger.With(slog.String("request_id", id)))
+			r = r.WithContext(ctx)
+
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("operation", MatchOperationID(router, r)),
+				slog.Int("status", sw.Status()),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.Int64("bytes", sw.bytes),
+			}
+			level := slog.LevelInfo
+			if sw.Status() >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logging.FromContext(ctx).LogAttrs(ctx, level, "request", attrs...)
+		})
+	}
+}
+
+// validRequestID accepts IDs of printable ASCII, so that they are safe to put
+// in headers and logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bersennaidoo/agentco/application/rest/server"
+	"github.com/bersennaidoo/agentco/physical/logging"
+	"github.com/bersennaidoo/agentco/physical/tracing"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/bersennaidoo/agentco/application/rest"
+
+// Trace starts a server span per operation, named by its operationId and
+// continuing the trace of an incoming traceparent header. The span records
+// the resource named by the path's {id} and the response status; Authenticate
+// adds the caller's user ID. It must be the first middleware to run, so that
+// the span covers the others.
+func Trace() server.MiddlewareFunc {
+	tracer := otel.Tracer(tracerName)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			route, _ := mux.CurrentRoute(r).GetPathTemplate()
+			name := OperationID(r)
+			if name == "" {
+				name = r.Method + " " + route
+			}
+
+			attrs := []attribute.KeyValue{
+				semconv.HTTPMethod(r.Method),
+				semconv.HTTPRoute(route),
+			}
+			if id, ok := mux.Vars(r)["id"]; ok {
+				attrs = append(attrs, pathIDKey(route).String(id))
+			}
+
+			ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+			defer span.End()
+
+			if sc := span.SpanContext(); sc.IsValid() {
+				logging.AddAttrs(ctx, slog.String("trace_id", sc.TraceID().String()))
+			}
+
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			span.SetAttributes(semconv.HTTPStatusCode(sw.Status()))
+			if sw.Status() >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(sw.Status()))
+			}
+		})
+	}
+}
+
+// pathIDKey returns the attribute for the {id} of route, which names a job,
+// a job application, a job series or a user depending on the collection it
+// follows.
+func pathIDKey(route string) attribute.Key {
+	switch {
+	case strings.Contains(route, "/job-applications/{id}"):
+		return tracing.JobApplicationIDKey
+	case strings.Contains(route, "/job-series/{id}"):
+		return tracing.JobSeriesIDKey
+	case strings.Contains(route, "/jobs/{id}"):
+		return tracing.JobIDKey
+	case strings.Contains(route, "/users/{id}"):
+		return tracing.UserIDKey
+	}
+
+	return attribute.Key("agentco.id")
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/application/rest/server"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/logging"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// ValidationOptions configures Validate.
+type ValidationOptions struct {
+	// ValidateResponses checks every response against the spec as well.
+	// Responses are buffered to do so, which makes it a mode for tests and
+	// staging rather than production.
+	ValidateResponses bool
+	// OnInvalidResponse is called with each response that does not match the
+	// spec. When nil, the response is logged and replaced by a 500 naming the
+	// mismatch, so that tests fail loudly.
+	OnInvalidResponse func(r *http.Request, err error)
+}
+
+// The calendar feed is the only response that is not JSON; validate it as
+// the plain string the spec declares.
+func init() {
+	openapi3filter.RegisterBodyDecoder("text/calendar", openapi3filter.FileBodyDecoder)
+}
+
+// Validate checks requests against the embedded OpenAPI spec before the
+// handler runs and rejects those that do not match with a validation_failed
+// problem listing each mismatch.
+// It expects to run after Authenticate, which is what satisfies the spec's
+// security requirements.
+func Validate(opts ValidationOptions) (server.MiddlewareFunc, error) {
+	swagger, err := server.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("loading spec: %w", err)
+	}
+	// Routes are served at the root, not under the spec's server URL.
+	swagger.Servers = nil
+
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("building spec router: %w", err)
+	}
+
+	filterOptions := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: authenticated,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				// The gorilla router already matched this request, so the
+				// spec router should too; don't turn a mismatch between the
+				// two into a client error.
+				logging.FromContext(r.Context()).Warn("spec router does not match a routed request", slog.Any("error", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    filterOptions,
+			}
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				writeValidationError(w, r, err)
+				return
+			}
+
+			if !opts.ValidateResponses {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &responseRecorder{header: make(http.Header), status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 rec.status,
+				Header:                 rec.header,
+				Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+				Options:                filterOptions,
+			})
+			if err != nil {
+				err = fmt.Errorf("%s %s: response does not match the spec: %w", r.Method, route.Path, err)
+				if opts.OnInvalidResponse != nil {
+					opts.OnInvalidResponse(r, err)
+				} else {
+					logging.FromContext(r.Context()).Error("invalid response", slog.Any("error", err))
+					problem.Write(w, r, problem.New(http.StatusInternalServerError, models.Internal, err.Error()))
+					return
+				}
+			}
+
+			rec.flush(w)
+		})
+	}, nil
+}
+
+// authenticated satisfies the SessionToken security scheme when
+// Authenticate has put a principal in the request context.
+func authenticated(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+	if _, ok := auth.PrincipalFromContext(input.RequestValidationInput.Request.Context()); !ok {
+		return errors.New("missing session token")
+	}
+
+	return nil
+}
+
+func writeValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	var security *openapi3filter.SecurityRequirementsError
+	if errors.As(err, &security) {
+		problem.Write(w, r, problem.Unauthenticated("missing session token"))
+		return
+	}
+
+	problem.Write(w, r, problem.Invalid("request does not match the API specification", fieldErrors(err)))
+}
+
+// fieldErrors flattens the errors kin-openapi reports for a request into one
+// ProblemField per problem. MultiErrors are taken apart by type assertion
+// rather than errors.As, since the latter would look through a RequestError
+// and lose which parameter it is about.
+func fieldErrors(err error) []models.ProblemField {
+	var fields []models.ProblemField
+	for _, e := range flatten(err) {
+		requestErr, ok := e.(*openapi3filter.RequestError)
+		if !ok {
+			fields = append(fields, problem.Field(models.Body, "", e.Error()))
+			continue
+		}
+
+		in, name := models.Body, ""
+		if p := requestErr.Parameter; p != nil {
+			in, name = models.ProblemFieldIn(p.In), p.Name
+		}
+		if requestErr.Err == nil {
+			fields = append(fields, problem.Field(in, name, requestErr.Reason))
+			continue
+		}
+
+		for _, cause := range flatten(requestErr.Err) {
+			fieldName, reason := name, cause.Error()
+			var schemaErr *openapi3.SchemaError
+			if errors.As(cause, &schemaErr) {
+				reason = schemaErr.Reason
+				if path := schemaErr.JSONPointer(); len(path) > 0 && fieldName == "" {
+					fieldName = strings.Join(path, ".")
+				}
+			}
+			fields = append(fields, problem.Field(in, fieldName, reason))
+		}
+	}
+
+	return fields
+}
+
+func flatten(err error) []error {
+	multi, ok := err.(openapi3.MultiError)
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range multi {
+		errs = append(errs, flatten(e)...)
+	}
+	return errs
+}
+
+// responseRecorder buffers a response so it can be validated before it is
+// sent.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	return rec.body.Write(b)
+}
+
+func (rec *responseRecorder) flush(w http.ResponseWriter) {
+	for key, values := range rec.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(rec.status)
+	w.Write(rec.body.Bytes())
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/gorilla/mux"
+)
+
+func TestValidateResponses(t *testing.T) {
+	offSpec := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Handler", "ran")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"description": 5, "activities": "walk"}`))
+	})
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "ran")
+		problem.Write(w, r, problem.NotFound("job not found"))
+	})
+
+	var reported []error
+	tests := []struct {
+		name     string
+		opts     ValidationOptions
+		handler  http.Handler
+		status   int
+		code     models.ProblemCode
+		detail   string
+		reported int
+	}{
+		{
+			name:    "responses not validated",
+			handler: offSpec,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "response matches the spec",
+			opts:    ValidationOptions{ValidateResponses: true},
+			handler: notFound,
+			status:  http.StatusNotFound,
+			code:    models.NotFound,
+		},
+		{
+			name:    "response off the spec",
+			opts:    ValidationOptions{ValidateResponses: true},
+			handler: offSpec,
+			status:  http.StatusInternalServerError,
+			code:    models.Internal,
+			detail:  "GET /jobs/{id}: response does not match the spec",
+		},
+		{
+			name: "response off the spec reported",
+			opts: ValidationOptions{ValidateResponses: true, OnInvalidResponse: func(r *http.Request, err error) {
+				reported = append(reported, err)
+			}},
+			handler:  offSpec,
+			status:   http.StatusOK,
+			reported: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported = nil
+			validate, err := Validate(tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			router := mux.NewRouter()
+			router.Handle("/jobs/{id}", validate(tt.handler)).Methods(http.MethodGet)
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/job-1", nil)
+			req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{UserId: "owner-1"}))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if len(reported) != tt.reported {
+				t.Fatalf("expected %d reported responses, got %v", tt.reported, reported)
+			}
+			if tt.code == "" {
+				if rec.Header().Get("X-Handler") != "ran" || !strings.Contains(rec.Body.String(), `"description": 5`) {
+					t.Fatalf("expected the handler's response, got %v %s", rec.Header(), rec.Body)
+				}
+				return
+			}
+
+			var p models.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Code != tt.code || p.Status != tt.status || p.Detail == nil || !strings.Contains(*p.Detail, tt.detail) {
+				t.Fatalf("expected a %s problem mentioning %q, got %+v", tt.code, tt.detail, p)
+			}
+			if tt.status == http.StatusInternalServerError && rec.Header().Get("X-Handler") != "" {
+				t.Fatalf("expected none of the handler's headers, got %v", rec.Header())
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Package problem reports errors as RFC 7807 problem details
+// (application/problem+json). Every problem carries a stable code from
+// models.ProblemCode and the ID of the request it answers.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/ratelimit"
+	"github.com/bersennaidoo/agentco/application/rest/requestid"
+	"github.com/bersennaidoo/agentco/application/rest/server"
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/logging"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ContentType is the media type of problem responses.
+const ContentType = "application/problem+json"
+
+// typePrefix turns a code into the problem's type URI.
+const typePrefix = "urn:agentco:problem:"
+
+// New returns a problem with the given status, code and detail.
+func New(status int, code models.ProblemCode, detail string) models.Problem {
+	p := models.Problem{
+		Type:   typePrefix + string(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+	}
+	if detail != "" {
+		p.Detail = &detail
+	}
+
+	return p
+}
+
+func BadRequest(detail string) models.Problem {
+	return New(http.StatusBadRequest, models.InvalidRequest, detail)
+}
+
+func Unauthenticated(detail string) models.Problem {
+	return New(http.StatusUnauthorized, models.Unauthenticated, detail)
+}
+
+func Forbidden(detail string) models.Problem {
+	return New(http.StatusForbidden, models.Forbidden, detail)
+}
+
+func NotFound(detail string) models.Problem {
+	return New(http.StatusNotFound, models.NotFound, detail)
+}
+
+func Conflict(detail string) models.Problem {
+	return New(http.StatusConflict, models.Conflict, detail)
+}
+
+func TooManyRequests(detail string) models.Problem {
+	return New(http.StatusTooManyRequests, models.RateLimited, detail)
+}
+
+// Invalid returns a 400 listing the fields that failed validation.
+func Invalid(detail string, fields []models.ProblemField) models.Problem {
+	p := New(http.StatusBadRequest, models.ValidationFailed, detail)
+	if len(fields) > 0 {
+		p.Errors = &fields
+	}
+
+	return p
+}
+
+// InvalidField returns a 400 for a single field that failed validation.
+func InvalidField(in models.ProblemFieldIn, name, reason string) models.Problem {
+	return Invalid(name+" "+reason, []models.ProblemField{Field(in, name, reason)})
+}
+
+// Field returns a ProblemField. in may be "" when the location is unknown.
+func Field(in models.ProblemFieldIn, name, reason string) models.ProblemField {
+	f := models.ProblemField{Reason: reason}
+	if in != "" {
+		f.In = &in
+	}
+	if name != "" {
+		f.Name = &name
+	}
+
+	return f
+}
+
+// Write sends p, filling in the request ID and the request path.
+func Write(w http.ResponseWriter, r *http.Request, p models.Problem) {
+	if id := requestid.FromContext(r.Context()); id != "" {
+		p.RequestId = &id
+	}
+	if p.Instance == nil {
+		instance := r.URL.Path
+		p.Instance = &instance
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Error writes the problem err maps to. Errors that have no client-facing
+// meaning are logged and reported as 500 Internal Server Error without
+// details.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	var locked *ratelimit.LockedError
+	if errors.As(err, &locked) {
+		w.Header().Set("Retry-After", strconv.Itoa(ratelimit.Seconds(locked.RetryAfter)))
+	}
+
+	p, ok := From(err)
+	if !ok {
+		logging.FromContext(r.Context()).Error("unexpected error", slog.String("method", r.Method), slog.String("path", r.URL.Path), slog.Any("error", err))
+	}
+
+	Write(w, r, p)
+}
+
+// From maps err to a problem. It reports false when err is unexpected and
+// the problem is a plain 500.
+func From(err error) (models.Problem, bool) {
+	var validation *domain.ValidationError
+	if errors.As(err, &validation) {
+		fields := make([]models.ProblemField, 0, len(validation.Violations))
+		for _, v := range validation.Violations {
+			fields = append(fields, Field("", v.Field, v.Reason))
+		}
+		return Invalid(validation.Error(), fields), true
+	}
+
+	var policy *auth.PolicyError
+	if errors.As(err, &policy) {
+		fields := make([]models.ProblemField, 0, len(policy.Violations))
+		for _, v := range policy.Violations {
+			fields = append(fields, Field(models.Body, "password", v))
+		}
+		return Invalid(policy.Error(), fields), true
+	}
+
+	if p, ok := fromParamError(err); ok {
+		return p, true
+	}
+
+	switch {
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		return New(http.StatusUnauthorized, models.InvalidCredentials, err.Error()), true
+	case errors.Is(err, auth.ErrInvalidToken):
+		return Unauthenticated(err.Error()), true
+	case errors.Is(err, domain.ErrValidation):
+		return Invalid(err.Error(), nil), true
+	case errors.Is(err, domain.ErrNotFound):
+		return NotFound(err.Error()), true
+	case errors.Is(err, domain.ErrConflict), mongo.IsDuplicateKeyError(err):
+		return Conflict("the resource conflicts with an existing one"), true
+	case errors.Is(err, domain.ErrForbidden):
+		return Forbidden(""), true
+	case errors.Is(err, ratelimit.ErrLocked):
+		return TooManyRequests(err.Error()), true
+	}
+
+	return New(http.StatusInternalServerError, models.Internal, ""), false
+}
+
+// fromParamError maps the errors the generated wrapper reports when it
+// cannot bind a parameter.
+func fromParamError(err error) (models.Problem, bool) {
+	var (
+		invalidFormat *server.InvalidParamFormatError
+		unmarshaling  *server.UnmarshalingParamError
+		required      *server.RequiredParamError
+		requiredHdr   *server.RequiredHeaderError
+		tooMany       *server.TooManyValuesForParamError
+		cookie        *server.UnescapedCookieParamError
+	)
+
+	var field models.ProblemField
+	switch {
+	case errors.As(err, &invalidFormat):
+		field = Field("", invalidFormat.ParamName, invalidFormat.Err.Error())
+	case errors.As(err, &unmarshaling):
+		field = Field("", unmarshaling.ParamName, unmarshaling.Err.Error())
+	case errors.As(err, &required):
+		field = Field("", required.ParamName, "value is required")
+	case errors.As(err, &requiredHdr):
+		field = Field(models.Header, requiredHdr.ParamName, "value is required")
+	case errors.As(err, &tooMany):
+		field = Field("", tooMany.ParamName, "expected one value")
+	case errors.As(err, &cookie):
+		field = Field(models.Cookie, cookie.ParamName, cookie.Err.Error())
+	default:
+		return models.Problem{}, false
+	}
+
+	p := New(http.StatusBadRequest, models.InvalidParameter, err.Error())
+	p.Errors = &[]models.ProblemField{field}
+	return p, true
+}
+
+// RouteNotFound answers requests no route matches.
+func RouteNotFound(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, NotFound("no route matches "+r.URL.Path))
+}
+
+// MethodNotAllowed answers requests whose path matches a route but whose
+// method does not. allowed returns the methods the path can be requested
+// with, which are listed in the Allow header.
+func MethodNotAllowed(allowed func(r *http.Request) []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", strings.Join(allowed(r), ", "))
+		Write(w, r, New(http.StatusMethodNotAllowed, models.MethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path))
+	})
+}
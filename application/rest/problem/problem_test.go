@@ -0,0 +1,247 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/ratelimit"
+	"github.com/bersennaidoo/agentco/application/rest/requestid"
+	"github.com/bersennaidoo/agentco/application/rest/server"
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+func TestFrom(t *testing.T) {
+	validation := &domain.ValidationError{}
+	validation.Add("ends_at", "must be after starts_at")
+	validation.Add("dog.size", "must be one of small, medium, large")
+
+	tests := []struct {
+		name     string
+		err      error
+		status   int
+		code     models.ProblemCode
+		expected bool
+		fields   []string
+		in       models.ProblemFieldIn
+	}{
+		{
+			name:     "validation error lists every field",
+			err:      fmt.Errorf("posting job: %w", validation),
+			status:   http.StatusBadRequest,
+			code:     models.ValidationFailed,
+			expected: true,
+			fields:   []string{"ends_at", "dog.size"},
+		},
+		{
+			name:     "password policy points at the body",
+			err:      &auth.PolicyError{Violations: []string{"must contain a digit"}},
+			status:   http.StatusBadRequest,
+			code:     models.ValidationFailed,
+			expected: true,
+			fields:   []string{"password"},
+			in:       models.Body,
+		},
+		{
+			name:     "invalid parameter format",
+			err:      &server.InvalidParamFormatError{ParamName: "limit", Err: errors.New("not a number")},
+			status:   http.StatusBadRequest,
+			code:     models.InvalidParameter,
+			expected: true,
+			fields:   []string{"limit"},
+		},
+		{
+			name:     "missing header",
+			err:      &server.RequiredHeaderError{ParamName: "If-Match"},
+			status:   http.StatusBadRequest,
+			code:     models.InvalidParameter,
+			expected: true,
+			fields:   []string{"If-Match"},
+			in:       models.Header,
+		},
+		{
+			name:     "invalid credentials",
+			err:      auth.ErrInvalidCredentials,
+			status:   http.StatusUnauthorized,
+			code:     models.InvalidCredentials,
+			expected: true,
+		},
+		{
+			name:     "invalid token",
+			err:      fmt.Errorf("authenticating: %w", auth.ErrInvalidToken),
+			status:   http.StatusUnauthorized,
+			code:     models.Unauthenticated,
+			expected: true,
+		},
+		{
+			name:     "bare validation sentinel",
+			err:      domain.ErrValidation,
+			status:   http.StatusBadRequest,
+			code:     models.ValidationFailed,
+			expected: true,
+		},
+		{
+			name:     "not found",
+			err:      fmt.Errorf("job 42: %w", domain.ErrNotFound),
+			status:   http.StatusNotFound,
+			code:     models.NotFound,
+			expected: true,
+		},
+		{
+			name:     "conflict",
+			err:      domain.ErrConflict,
+			status:   http.StatusConflict,
+			code:     models.Conflict,
+			expected: true,
+		},
+		{
+			name:     "schedule conflict",
+			err:      &domain.ScheduleConflictError{JobId: "42"},
+			status:   http.StatusConflict,
+			code:     models.Conflict,
+			expected: true,
+		},
+		{
+			name:     "forbidden",
+			err:      fmt.Errorf("put_jobs_id on 42: %w", domain.ErrForbidden),
+			status:   http.StatusForbidden,
+			code:     models.Forbidden,
+			expected: true,
+		},
+		{
+			name:     "locked out",
+			err:      &ratelimit.LockedError{RetryAfter: time.Minute},
+			status:   http.StatusTooManyRequests,
+			code:     models.RateLimited,
+			expected: true,
+		},
+		{
+			name:   "unexpected error",
+			err:    errors.New("connection reset"),
+			status: http.StatusInternalServerError,
+			code:   models.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := From(tt.err)
+			if ok != tt.expected {
+				t.Fatalf("expected ok %t, got %t", tt.expected, ok)
+			}
+			if p.Status != tt.status || p.Code != tt.code {
+				t.Fatalf("expected %d %s, got %d %s", tt.status, tt.code, p.Status, p.Code)
+			}
+			if p.Type != typePrefix+string(tt.code) {
+				t.Fatalf("unexpected type %s", p.Type)
+			}
+			if p.Title != http.StatusText(tt.status) {
+				t.Fatalf("unexpected title %s", p.Title)
+			}
+
+			if tt.fields == nil {
+				if p.Errors != nil {
+					t.Fatalf("expected no fields, got %+v", *p.Errors)
+				}
+				return
+			}
+			if p.Errors == nil || len(*p.Errors) != len(tt.fields) {
+				t.Fatalf("expected fields %v, got %+v", tt.fields, p.Errors)
+			}
+			for i, field := range *p.Errors {
+				if field.Name == nil || *field.Name != tt.fields[i] {
+					t.Fatalf("expected field %s, got %+v", tt.fields[i], field)
+				}
+				if tt.in == "" && field.In != nil || tt.in != "" && (field.In == nil || *field.In != tt.in) {
+					t.Fatalf("expected field %s in %q, got %+v", tt.fields[i], tt.in, field.In)
+				}
+			}
+		})
+	}
+}
+
+func TestFromHidesUnexpectedErrors(t *testing.T) {
+	p, _ := From(errors.New("mongo: password=hunter2"))
+	if p.Detail != nil {
+		t.Fatalf("expected no detail, got %s", *p.Detail)
+	}
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		retryAfter string
+	}{
+		{name: "not found", err: domain.ErrNotFound, status: http.StatusNotFound},
+		{name: "locked out rounds retry up", err: &ratelimit.LockedError{RetryAfter: 1500 * time.Millisecond}, status: http.StatusTooManyRequests, retryAfter: "2"},
+		{name: "wrapped lockout", err: fmt.Errorf("logging in: %w", &ratelimit.LockedError{RetryAfter: time.Minute}), status: http.StatusTooManyRequests, retryAfter: "60"},
+		{name: "unexpected", err: errors.New("boom"), status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/jobs/42?limit=5", nil)
+			r = r.WithContext(requestid.With(r.Context(), "req-1"))
+			w := httptest.NewRecorder()
+
+			Error(w, r, tt.err)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != ContentType {
+				t.Fatalf("expected content type %s, got %s", ContentType, got)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Fatalf("expected Retry-After %q, got %q", tt.retryAfter, got)
+			}
+
+			var p models.Problem
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Status != tt.status {
+				t.Fatalf("expected body status %d, got %d", tt.status, p.Status)
+			}
+			if p.RequestId == nil || *p.RequestId != "req-1" {
+				t.Fatalf("expected request id req-1, got %v", p.RequestId)
+			}
+			if p.Instance == nil || *p.Instance != "/jobs/42" {
+				t.Fatalf("expected instance /jobs/42, got %v", p.Instance)
+			}
+		})
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	handler := MethodNotAllowed(func(r *http.Request) []string {
+		return []string{http.MethodGet, http.MethodPut, http.MethodDelete}
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/jobs/42", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "GET, PUT, DELETE" {
+		t.Fatalf("expected Allow GET, PUT, DELETE, got %q", got)
+	}
+
+	var p models.Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Code != models.MethodNotAllowed {
+		t.Fatalf("expected code %s, got %s", models.MethodNotAllowed, p.Code)
+	}
+}
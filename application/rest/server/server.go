@@ -25,6 +25,18 @@ type ServerInterface interface {
 	// Update application details
 	// (PUT /job-applications/{id})
 	UpdateJobApplication(w http.ResponseWriter, r *http.Request, id string)
+	// Post new Job Series
+	// (POST /job-series)
+	PostJobSeries(w http.ResponseWriter, r *http.Request)
+	// Get Job Series Details
+	// (GET /job-series/{id})
+	GetJobSeriesId(w http.ResponseWriter, r *http.Request, id string)
+	// Update the rest of a Job Series
+	// (PUT /job-series/{id})
+	PutJobSeriesId(w http.ResponseWriter, r *http.Request, id string, params models.PutJobSeriesIdParams)
+	// Apply to every occurrence of a Job Series
+	// (POST /job-series/{id}/job-applications)
+	ApplyToJobSeries(w http.ResponseWriter, r *http.Request, id string)
 	// List available jobs
 	// (GET /jobs)
 	GetJobs(w http.ResponseWriter, r *http.Request, params models.GetJobsParams)
@@ -46,6 +58,9 @@ type ServerInterface interface {
 	// Create a job application
 	// (POST /jobs/{id}/job-applications)
 	CreateJobApplication(w http.ResponseWriter, r *http.Request, id string)
+	// Find sitters for a job
+	// (GET /jobs/{id}/matches)
+	GetJobMatches(w http.ResponseWriter, r *http.Request, id string, params models.GetJobMatchesParams)
 	// Start Session (Login)
 	// (POST /sessions)
 	StartSession(w http.ResponseWriter, r *http.Request)
@@ -61,12 +76,42 @@ type ServerInterface interface {
 	// Update User Account
 	// (PUT /users/{id})
 	PutUsersId(w http.ResponseWriter, r *http.Request, id string)
+	// Get a PetSitter's availability
+	// (GET /users/{id}/availability)
+	GetUserAvailability(w http.ResponseWriter, r *http.Request, id string)
+	// Set a PetSitter's availability
+	// (PUT /users/{id}/availability)
+	PutUserAvailability(w http.ResponseWriter, r *http.Request, id string)
+	// Revoke the user's calendar feed
+	// (DELETE /users/{id}/calendar-feed)
+	DeleteCalendarFeed(w http.ResponseWriter, r *http.Request, id string)
+	// Create the user's calendar feed link
+	// (POST /users/{id}/calendar-feed)
+	PostCalendarFeed(w http.ResponseWriter, r *http.Request, id string)
+	// Get the user's calendar feed
+	// (GET /users/{id}/calendar.ics)
+	GetCalendarFeed(w http.ResponseWriter, r *http.Request, id string, params models.GetCalendarFeedParams)
 	// Get a list of Job Applications that are associated with this user.
 	// (GET /users/{id}/job-applications)
-	GetJobApplicationsForUser(w http.ResponseWriter, r *http.Request, id string)
+	GetJobApplicationsForUser(w http.ResponseWriter, r *http.Request, id string, params models.GetJobApplicationsForUserParams)
 	// Get a list of Jobs that are associated with this user.
 	// (GET /users/{id}/jobs)
-	GetJobsForUser(w http.ResponseWriter, r *http.Request, id string)
+	GetJobsForUser(w http.ResponseWriter, r *http.Request, id string, params models.GetJobsForUserParams)
+	// List a user's pets
+	// (GET /users/{id}/pets)
+	GetUserPets(w http.ResponseWriter, r *http.Request, id string)
+	// Add a pet
+	// (POST /users/{id}/pets)
+	PostUserPet(w http.ResponseWriter, r *http.Request, id string)
+	// Delete a pet
+	// (DELETE /users/{id}/pets/{pet_id})
+	DeleteUserPet(w http.ResponseWriter, r *http.Request, id string, petId string)
+	// Get a pet
+	// (GET /users/{id}/pets/{pet_id})
+	GetUserPet(w http.ResponseWriter, r *http.Request, id string, petId string)
+	// Update a pet
+	// (PUT /users/{id}/pets/{pet_id})
+	PutUserPet(w http.ResponseWriter, r *http.Request, id string, petId string)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -134,6 +179,118 @@ func (siw *ServerInterfaceWrapper) UpdateJobApplication(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// PostJobSeries operation middleware
+func (siw *ServerInterfaceWrapper) PostJobSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostJobSeries(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetJobSeriesId operation middleware
+func (siw *ServerInterfaceWrapper) GetJobSeriesId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetJobSeriesId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PutJobSeriesId operation middleware
+func (siw *ServerInterfaceWrapper) PutJobSeriesId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params models.PutJobSeriesIdParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutJobSeriesId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ApplyToJobSeries operation middleware
+func (siw *ServerInterfaceWrapper) ApplyToJobSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ApplyToJobSeries(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // GetJobs operation middleware
 func (siw *ServerInterfaceWrapper) GetJobs(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -153,16 +310,350 @@ func (siw *ServerInterfaceWrapper) GetJobs(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// ------------- Optional query parameter "offset" -------------
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "activity" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "activity", r.URL.Query(), &params.Activity)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "activity", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "dog_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dog_size", r.URL.Query(), &params.DogSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dog_size", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "starts_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "starts_after", r.URL.Query(), &params.StartsAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "starts_after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "ends_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "ends_before", r.URL.Query(), &params.EndsBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "ends_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "open" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "open", r.URL.Query(), &params.Open)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "open", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "fits_schedule" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fits_schedule", r.URL.Query(), &params.FitsSchedule)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fits_schedule", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "series_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "series_id", r.URL.Query(), &params.SeriesId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "series_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "creator_user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "creator_user_id", r.URL.Query(), &params.CreatorUserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "creator_user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "order", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetJobs(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostJobs operation middleware
+func (siw *ServerInterfaceWrapper) PostJobs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostJobs(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteJobsId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteJobsId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteJobsId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetJobsId operation middleware
+func (siw *ServerInterfaceWrapper) GetJobsId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetJobsId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PutJobsId operation middleware
+func (siw *ServerInterfaceWrapper) PutJobsId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutJobsId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetApplicationsByJobId operation middleware
+func (siw *ServerInterfaceWrapper) GetApplicationsByJobId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetApplicationsByJobId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateJobApplication operation middleware
+func (siw *ServerInterfaceWrapper) CreateJobApplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateJobApplication(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetJobMatches operation middleware
+func (siw *ServerInterfaceWrapper) GetJobMatches(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params models.GetJobMatchesParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetJobMatches(w, r, id, params)
+	}))
 
-	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StartSession operation middleware
+func (siw *ServerInterfaceWrapper) StartSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StartSession(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostUsers operation middleware
+func (siw *ServerInterfaceWrapper) PostUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteUsersId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUsersId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetJobs(w, r, params)
+		siw.Handler.DeleteUsersId(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -172,14 +663,25 @@ func (siw *ServerInterfaceWrapper) GetJobs(w http.ResponseWriter, r *http.Reques
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostJobs operation middleware
-func (siw *ServerInterfaceWrapper) PostJobs(w http.ResponseWriter, r *http.Request) {
+// GetUsersId operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersId(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostJobs(w, r)
+		siw.Handler.GetUsersId(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -189,8 +691,8 @@ func (siw *ServerInterfaceWrapper) PostJobs(w http.ResponseWriter, r *http.Reque
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// DeleteJobsId operation middleware
-func (siw *ServerInterfaceWrapper) DeleteJobsId(w http.ResponseWriter, r *http.Request) {
+// PutUsersId operation middleware
+func (siw *ServerInterfaceWrapper) PutUsersId(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -207,7 +709,7 @@ func (siw *ServerInterfaceWrapper) DeleteJobsId(w http.ResponseWriter, r *http.R
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteJobsId(w, r, id)
+		siw.Handler.PutUsersId(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -217,8 +719,8 @@ func (siw *ServerInterfaceWrapper) DeleteJobsId(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetJobsId operation middleware
-func (siw *ServerInterfaceWrapper) GetJobsId(w http.ResponseWriter, r *http.Request) {
+// GetUserAvailability operation middleware
+func (siw *ServerInterfaceWrapper) GetUserAvailability(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -235,7 +737,7 @@ func (siw *ServerInterfaceWrapper) GetJobsId(w http.ResponseWriter, r *http.Requ
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetJobsId(w, r, id)
+		siw.Handler.GetUserAvailability(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -245,8 +747,8 @@ func (siw *ServerInterfaceWrapper) GetJobsId(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PutJobsId operation middleware
-func (siw *ServerInterfaceWrapper) PutJobsId(w http.ResponseWriter, r *http.Request) {
+// PutUserAvailability operation middleware
+func (siw *ServerInterfaceWrapper) PutUserAvailability(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -263,7 +765,7 @@ func (siw *ServerInterfaceWrapper) PutJobsId(w http.ResponseWriter, r *http.Requ
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PutJobsId(w, r, id)
+		siw.Handler.PutUserAvailability(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -273,8 +775,8 @@ func (siw *ServerInterfaceWrapper) PutJobsId(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetApplicationsByJobId operation middleware
-func (siw *ServerInterfaceWrapper) GetApplicationsByJobId(w http.ResponseWriter, r *http.Request) {
+// DeleteCalendarFeed operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCalendarFeed(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -291,7 +793,7 @@ func (siw *ServerInterfaceWrapper) GetApplicationsByJobId(w http.ResponseWriter,
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetApplicationsByJobId(w, r, id)
+		siw.Handler.DeleteCalendarFeed(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -301,8 +803,8 @@ func (siw *ServerInterfaceWrapper) GetApplicationsByJobId(w http.ResponseWriter,
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// CreateJobApplication operation middleware
-func (siw *ServerInterfaceWrapper) CreateJobApplication(w http.ResponseWriter, r *http.Request) {
+// PostCalendarFeed operation middleware
+func (siw *ServerInterfaceWrapper) PostCalendarFeed(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -319,7 +821,7 @@ func (siw *ServerInterfaceWrapper) CreateJobApplication(w http.ResponseWriter, r
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateJobApplication(w, r, id)
+		siw.Handler.PostCalendarFeed(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -329,12 +831,41 @@ func (siw *ServerInterfaceWrapper) CreateJobApplication(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// StartSession operation middleware
-func (siw *ServerInterfaceWrapper) StartSession(w http.ResponseWriter, r *http.Request) {
+// GetCalendarFeed operation middleware
+func (siw *ServerInterfaceWrapper) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params models.GetCalendarFeedParams
+
+	// ------------- Required query parameter "token" -------------
+
+	if paramValue := r.URL.Query().Get("token"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "token"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "token", r.URL.Query(), &params.Token)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.StartSession(w, r)
+		siw.Handler.GetCalendarFeed(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -344,12 +875,115 @@ func (siw *ServerInterfaceWrapper) StartSession(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostUsers operation middleware
-func (siw *ServerInterfaceWrapper) PostUsers(w http.ResponseWriter, r *http.Request) {
+// GetJobApplicationsForUser operation middleware
+func (siw *ServerInterfaceWrapper) GetJobApplicationsForUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params models.GetJobApplicationsForUserParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetJobApplicationsForUser(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetJobsForUser operation middleware
+func (siw *ServerInterfaceWrapper) GetJobsForUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameter("simple", false, "id", mux.Vars(r)["id"], &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params models.GetJobsForUserParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "role" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "role", r.URL.Query(), &params.Role)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "role", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostUsers(w, r)
+		siw.Handler.GetJobsForUser(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -359,8 +993,8 @@ func (siw *ServerInterfaceWrapper) PostUsers(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// DeleteUsersId operation middleware
-func (siw *ServerInterfaceWrapper) DeleteUsersId(w http.ResponseWriter, r *http.Request) {
+// GetUserPets operation middleware
+func (siw *ServerInterfaceWrapper) GetUserPets(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -377,7 +1011,7 @@ func (siw *ServerInterfaceWrapper) DeleteUsersId(w http.ResponseWriter, r *http.
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteUsersId(w, r, id)
+		siw.Handler.GetUserPets(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -387,8 +1021,8 @@ func (siw *ServerInterfaceWrapper) DeleteUsersId(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetUsersId operation middleware
-func (siw *ServerInterfaceWrapper) GetUsersId(w http.ResponseWriter, r *http.Request) {
+// PostUserPet operation middleware
+func (siw *ServerInterfaceWrapper) PostUserPet(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -405,7 +1039,7 @@ func (siw *ServerInterfaceWrapper) GetUsersId(w http.ResponseWriter, r *http.Req
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetUsersId(w, r, id)
+		siw.Handler.PostUserPet(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -415,8 +1049,8 @@ func (siw *ServerInterfaceWrapper) GetUsersId(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PutUsersId operation middleware
-func (siw *ServerInterfaceWrapper) PutUsersId(w http.ResponseWriter, r *http.Request) {
+// DeleteUserPet operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUserPet(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -430,10 +1064,19 @@ func (siw *ServerInterfaceWrapper) PutUsersId(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// ------------- Path parameter "pet_id" -------------
+	var petId string
+
+	err = runtime.BindStyledParameter("simple", false, "pet_id", mux.Vars(r)["pet_id"], &petId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pet_id", Err: err})
+		return
+	}
+
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PutUsersId(w, r, id)
+		siw.Handler.DeleteUserPet(w, r, id, petId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -443,8 +1086,8 @@ func (siw *ServerInterfaceWrapper) PutUsersId(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetJobApplicationsForUser operation middleware
-func (siw *ServerInterfaceWrapper) GetJobApplicationsForUser(w http.ResponseWriter, r *http.Request) {
+// GetUserPet operation middleware
+func (siw *ServerInterfaceWrapper) GetUserPet(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -458,10 +1101,19 @@ func (siw *ServerInterfaceWrapper) GetJobApplicationsForUser(w http.ResponseWrit
 		return
 	}
 
+	// ------------- Path parameter "pet_id" -------------
+	var petId string
+
+	err = runtime.BindStyledParameter("simple", false, "pet_id", mux.Vars(r)["pet_id"], &petId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pet_id", Err: err})
+		return
+	}
+
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetJobApplicationsForUser(w, r, id)
+		siw.Handler.GetUserPet(w, r, id, petId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -471,8 +1123,8 @@ func (siw *ServerInterfaceWrapper) GetJobApplicationsForUser(w http.ResponseWrit
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetJobsForUser operation middleware
-func (siw *ServerInterfaceWrapper) GetJobsForUser(w http.ResponseWriter, r *http.Request) {
+// PutUserPet operation middleware
+func (siw *ServerInterfaceWrapper) PutUserPet(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -486,10 +1138,19 @@ func (siw *ServerInterfaceWrapper) GetJobsForUser(w http.ResponseWriter, r *http
 		return
 	}
 
+	// ------------- Path parameter "pet_id" -------------
+	var petId string
+
+	err = runtime.BindStyledParameter("simple", false, "pet_id", mux.Vars(r)["pet_id"], &petId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pet_id", Err: err})
+		return
+	}
+
 	ctx = context.WithValue(ctx, models.SessionTokenScopes, []string{})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetJobsForUser(w, r, id)
+		siw.Handler.PutUserPet(w, r, id, petId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -616,6 +1277,14 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/job-applications/{id}", wrapper.UpdateJobApplication).Methods("PUT")
 
+	r.HandleFunc(options.BaseURL+"/job-series", wrapper.PostJobSeries).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/job-series/{id}", wrapper.GetJobSeriesId).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/job-series/{id}", wrapper.PutJobSeriesId).Methods("PUT")
+
+	r.HandleFunc(options.BaseURL+"/job-series/{id}/job-applications", wrapper.ApplyToJobSeries).Methods("POST")
+
 	r.HandleFunc(options.BaseURL+"/jobs", wrapper.GetJobs).Methods("GET")
 
 	r.HandleFunc(options.BaseURL+"/jobs", wrapper.PostJobs).Methods("POST")
@@ -630,6 +1299,8 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/jobs/{id}/job-applications", wrapper.CreateJobApplication).Methods("POST")
 
+	r.HandleFunc(options.BaseURL+"/jobs/{id}/matches", wrapper.GetJobMatches).Methods("GET")
+
 	r.HandleFunc(options.BaseURL+"/sessions", wrapper.StartSession).Methods("POST")
 
 	r.HandleFunc(options.BaseURL+"/users", wrapper.PostUsers).Methods("POST")
@@ -640,50 +1311,144 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/users/{id}", wrapper.PutUsersId).Methods("PUT")
 
+	r.HandleFunc(options.BaseURL+"/users/{id}/availability", wrapper.GetUserAvailability).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/users/{id}/availability", wrapper.PutUserAvailability).Methods("PUT")
+
+	r.HandleFunc(options.BaseURL+"/users/{id}/calendar-feed", wrapper.DeleteCalendarFeed).Methods("DELETE")
+
+	r.HandleFunc(options.BaseURL+"/users/{id}/calendar-feed", wrapper.PostCalendarFeed).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/users/{id}/calendar.ics", wrapper.GetCalendarFeed).Methods("GET")
+
 	r.HandleFunc(options.BaseURL+"/users/{id}/job-applications", wrapper.GetJobApplicationsForUser).Methods("GET")
 
 	r.HandleFunc(options.BaseURL+"/users/{id}/jobs", wrapper.GetJobsForUser).Methods("GET")
 
+	r.HandleFunc(options.BaseURL+"/users/{id}/pets", wrapper.GetUserPets).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/users/{id}/pets", wrapper.PostUserPet).Methods("POST")
+
+	r.HandleFunc(options.BaseURL+"/users/{id}/pets/{pet_id}", wrapper.DeleteUserPet).Methods("DELETE")
+
+	r.HandleFunc(options.BaseURL+"/users/{id}/pets/{pet_id}", wrapper.GetUserPet).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/users/{id}/pets/{pet_id}", wrapper.PutUserPet).Methods("PUT")
+
 	return r
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xZXW/jNhb9KwR3H9qBkmjSdhfQm5tMB0mDSdBksNjNBgZtXdvMUKRKUvG4hv97cUnJ",
-	"pmQmceJ8zGDmJZEpirwfh+ceknM6VEWpJEhraDanZjiBgrnHYzXAf/CZFaUAfGRDy2+45WBodkmnTHyi",
-	"if93lVBWloIPmeVK4us55TnN8E9Cr9Wg737VDwk1ltnK0Iz2zs5O/nv04T1NaGVA+27N0yJ5klGuEjrU",
-	"wCzkfWZpRvfTNN1J3+7s/3SR/pz98q8s/ff/aN1H6f5qhG5LQnMwQ81LdJJmrV8JzdUYgzTQAPix/59Q",
-	"yQqgmf+XUMP/wl+mYELQhM6AadNXIqdZukgoyNzcaeMqGsYybe/uXJX5vU5Plf4Eoc+dhkVCS61K0D7r",
-	"bQzMKbdQuAeQVRGAIteq5BiUgWI653LsXLfWP+VsNmQaEDV2Vrp4WI2vFgktuDwBObYTmr1dvmZasxm+",
-	"bYMsmP+fGkY0o//YW8F5r8by3rEa9Fbf4TDdYUN4zOlI6cLFDMO3Y7lLnAaWn0oxo5nVFUQMX4PPvIOW",
-	"iwkQfEmmE0VKZSzkxE64IddqsLvJDK3h5pH3HoD3hKKP3UKsxexEzwmTOUHvyXQCcmkqwQ/R3miY1ozy",
-	"kbjXtwDOD7XHf7q5ReGyeGyyu8uma/N/vIXgDOSGqBJkQmQlxC7pvhtxISBPXNMSH9wQnIPLMVGScIvu",
-	"4edsgFTsrbrHygX2+LPiGunoMly4SYe5GiiEacC1yS1O5grBcnQ1uIahxRh0llW7VDwF+XeYJxbnYzUg",
-	"AScQnm+0khqbYsM5EzfBq/MBRxixSti2Ow0dBk29g4N3ZxfvDmlCD999OHp3GKW/WxF1lBM18vjArIJB",
-	"LkUI0U2AEKQyzFk8q/2aSIJ0blPU2lmsR4rQlx8z8sIPv6oxzTwF5LwqaEIF0+N4NQlMWQ7MpYUx6LUF",
-	"0vjmpgs/vYqE6RyMqVHfqY+VnfQnwHLQUWeCBN+epmb0yMQfjR84SM4m4gYKxgXNKLJkJUS/TuDqOdQW",
-	"JTNmqnTu+2slvOA7A3s6laBpsnq82kRmrIHgKept7VHwuW+JdA08nj+6Qq2CEky5bIx8UMctIpLagTzn",
-	"1rrnXl5w+QhRtH1B66yEJpAhOrw3QVVwQIwAlEvBJfQ1mFJJA/39NO3gdcJMv1B6Wcfq+Fx+32B8kxuM",
-	"72n/FtOOTKIsE/169a8rhRVNrGkhmSMiwBA7YZZgJ+KGIUwDYTeMO53sNgxDJskAiAarOdxATqbcTkjO",
-	"RyPQIC1Ro5EB67oKXnBLSqZZARa021LU9DZQSgBze8clm2+694xtOFuuxzY8rgORVTEAjcLPdU2IhjHT",
-	"uQBjsHGipqRgcub3Qg3jEi6HosohtD/UPB3GRoEFw0pzOztHo33wawVyoT6BEzkcDatlzRJ7vcpOlOZ/",
-	"dcQkK/nvMKOLhasGI+Wqbl01emOQ9kCR3tkRTegNaK+i6NvdFOOC+yRWcprRn3bT3dRpETtxFu1dq8FO",
-	"SAZ7c54vfPQEWAcThI97eYSQO3Tta5J3lWBHJ/C5FCoHmo2YMFiNpKvrdrLys94SNAXSVy2f4bhgtTPn",
-	"rOGu5CHWm+Q4V/bTn+/fyuRgGRfGJ6gqCqZnS5/Cjhh3NnbMeawGrkR/3jFTNh6D3tGqsqB3hkparYRA",
-	"6eh74Wqr7HrMPrq1/aXEzO1zflX5zIlGJS1IZ3Pg/t618TJ8NfZDzoIW3XOVOgJuNx5Nx1ou0wcZ9xQn",
-	"VpjhesKd5ZrrTBrxDBHWuxdhtf8x3x+HtEXi1q5zegwRzL0H63quwaxt/gnSs3GJWfGiBlOJuhVkXiou",
-	"LZJ9paXjvyVSa6mJA/1ZgZ6tkOpon4bgXG7p99OEFuwzL1C3/5I6Ie5/pOvUusIwSnAnMtoOnH/ipbPU",
-	"gKdtX7NGWhXO/gZXG9rtK1fc8A3Mu9oSyHfhN7YHiADy9PcO9k64sUH9vvaoeCS9KRPB2pkyDdieiV7Q",
-	"0XWSePvUU3RjeeCVKE3qKu3mPVHh4Vybsx/AxYt2ljCGRMKU1AeDW3DC5jXcHOVfbu3+oMhBndt2qP6A",
-	"Qt3AFoFK7qTMLyYm6XPDe40q3oPFsJLDrWrTLSrorHr18L4gN7188mqNsX3+WjyytkO4S3EEQsj8OjtW",
-	"g690Lb2inmxKzvrCZEKEAtKQkdKtu86nLOnejG9py/LMS/ieDVI87b6VMHeruf32FBe28WcQzsV46s8t",
-	"03Z1WfLY6LfPnZYXC3deAUQubzrnKs+apsbpO9i2PtSh2eVVmCgXM1J/T344UWMufwyy9NHgkrk3Tb6b",
-	"y1Nlarl5u+T2vZ9nfbiLiGcW3c0cL6+6b8niHzDmxoImaBnpDYeqknb7LG6ox91nX6Ugr8/PniRqt2vz",
-	"Lys+6bMvg6g6dzE+kv4+sl0JHhrn20T6q8f5JdnsFdJY6/Rn4JgHafW2JjG/KV1fPn+X6w+Q6z0iuLFE",
-	"jfy5WkS1Lzt0TorrezZ3uWaMGnKsef4izYl6TOuaqk82xcdK8LXhEUKi7ckOefPm4vTw9M0b8huXOVGV",
-	"JWyAf93JL5dj4iJlSM41DK2YkR/QWuwxAHw91awsISdcEiYJyBsQqoQfd/+PLBU9avkWQPc6SHsRdLWl",
-	"VPd+8/IKY2xA3zRZrbSgGd1jJfdX1X7ueZPRpjItG/w0V4u/AwAA//+miwkAUC8AAA==",
+	"H4sIAAAAAAAC/+x9bXfbNpbwX8HhM+e0PQ8tK2m7napnPmgcZ5qOk2ZsZ2ezkVeFyCsJMQWwAGhFzfq/",
+	"77kXIAVS1Itt2UmafElkEgQuLu4b7gvwPkrULFcSpDVR732kweRKGqA/Xmo1ymCGPxMlLUiLP3meZyLh",
+	"Vih5mLsW//+tURLfmWQKM46//qJhHPWi/3e47P/QvTWHZb/X19dxlIJJtMixu6gXnU+Bafi9AGPZmIsM",
+	"0k50HUfnSj3ncnHq3piHhijJBEjL4F0CkELKhDVMcwssEzNhY6Y0s1NgPElUIS0ThmUquYSU8bEFzTTk",
+	"wC2kfkYsUxMhTYedgtUL3wa/n4grkCyFjC86URxNgaegabKn3MIJjnVA/+KjOpQlZhjPMjWHlOWg2VzI",
+	"VM2xqyUW7CKHqBcJaWECOsL5Ljs/hRkXUsjJhgEyGFsmJAGcFFojYm4xkIGWWZxBomRqWCGtyGgEQjAi",
+	"dFxk2YJpMFZpSLcPhag96CNq1w9jFZtzYdkIxkoD0/iNkJMtnRONuPe0Nv0rLjI+Epmwi9Wx/j0FyTh7",
+	"CfZMWFzohEs2AjZSighEpmw+5RYnu2BqPAaNw+da5aCtcHzIEyuuRPnXKnku3xPOjBuIOjPYm7AwM9tY",
+	"4Bc1OlwOe7js89B9fh1HMyFPQE7sNOo9ikvEcK35At+OMp5cqsK2ASlmddiEYXzOcT0nXKcZGMPUmBrM",
+	"AS6zBTOZsrvDHi7B3z0Y0fUqiKmaDI34Yx0eUzVh9DoENddAeGTHs9wu2Ay4NEwq/xxkAjdB8TBVkxDN",
+	"OFwbpFbM4A8loR3QZ/0XfYZNGLZZwRvjGpiQCBe847M8w677Yy0SfviLmnIpwYwKPYmqcY3VyPXXcVTk",
+	"KYqqISf2HCs9w18RPjzAEaM40sDTX2W2iHpWF9DWhwE9FKnTJ1vaOriHBHf7ZG0L8Yw1AIMr0AuaeIed",
+	"0bxnfMHUFeiM5zGxFieEMJCpkBPGLXv8Xa/bZW+VkK5LCe8sS/niK+NaGsu19W273V63eysaRGhWV/Ua",
+	"Ufd7ITSkUe9NyNXBejcwcoGvrFvAYIDlyqnRW0hosFYm6L1vyBKQqdm0uCsLpIF7VbryipB1k94aGChh",
+	"CXtaM+FqQlsmTphfmXTKF27uxQzHnSmJT+LIFmDcrzmksvxtp4X2P8dauB+G20L7nwV9fdGCK5DpKg2f",
+	"qIRnjlvVGGktZj//3Hv+PPaan+be8ZQpHFmCTEuBmPJFnZEf/dD7thvFUc6tBY0j/M/XX7/pPrp40z34",
+	"8eJ/H7/pHnx78U3vTffg+/IRdv3NX9pWlwbfHeY6JN2/9rpNSDYB8petFOEwjGgsQVtDD7TOLbRwxDOQ",
+	"KddPAdJVOih01i5jXp2eoDVgihG+GQGzqsP6coHSdaoykh7Cku5GiUYLMwZnh2wRcdfLCdSAawH+FzUi",
+	"Mi0RXNf8b6I5zy6j2P13EYdmL75+H6HIxX/i6K0akQAufxA2bWEQiy9fnrx+9uIfUSCnq1/X8V56uYij",
+	"REOlR6LH3W73oPvo4PG3593vet//R6/7w39Hvo3Sw2UPzSdNYzz8i9Q5ImmkabH9/3Ek+QyinvsvjkjD",
+	"9iIz41kWxdECuDZDlaVRr+s41myEcYmNQNqtaxwqz3Vt5kpfQjjnxoPrzdZfpYtKYeaJItUqF4iUkeIa",
+	"6ZWmbq37lfJFwjW0Cq3NVl2dyN7vbOj0l9+12TchedzWzFghnzbOxpdsPlUsV8YCcq4w7K0adXYZodbd",
+	"+7b3lidTaBtaF8DmZPtLphK3UUpImnIcnhnQAgybc8MgFQiZkrSvVHPZYccp/rSKBI0GY0tt4D/LgF8B",
+	"SiSeKQkb5jJSKgMuvfG7o4HqW7fYY31mJM/NVBFAzgBDq9lD91aNYjYXdkoz4RNgbmfjdFzQyqEGJapC",
+	"426s1YxxloO9qS0dXa+dekVrgcnTYvNzC2QqkrIjsEoSQS1Me5DdDKUdDd4c7FCka2zdHKwJ0PSVYZ7I",
+	"K8Sh8at0h537vy8BcsN4fV14MsUtOq5hzCy/BFnOrOrE8CtInZGccCkVkpJRzIDFz2rL0CYxnrmXLQJD",
+	"Q0nsrTz5VOnNLBEHBINU5J6yiSP4DntGRvrC7wj4ctEg7FQYNlNXkK5dvq3r5MZdK1cCHq7oRZiVqe0k",
+	"Zmp29M1I1H26O5HuY3vX1GCtLo+A1FQOMmayyLIOa74biyxDOrShqBaG4RhodJFQxOnh53yUQQnVdqtr",
+	"7V6rbkRs2YSgTdZuqoUarm617cMOaxgBbXj+RY1YoJ6ZSHeithKmtu5EuksP5RywhzEvMlufTmmZBI/6",
+	"R0fHL8+Pn0Rx9OT4xbPjJ62WyFqKeka7IXxdOoeRNt6q0XZor2tLGa5Z+6qeEU+3aT6kWIuuOufONTEz",
+	"RTJlHKUvGmGBP8Lv2Xay4+7VJ+fJQ9qSW9foHedZMcR99A2kpfUxn6qstDtI7SzIvRS0cvMOxJ437ljG",
+	"yQ8pEUdzyLLNWmWbGv+wJqNHwF6sxhsZYhutl8BPMBba2GAVOuy4uS4ZpwgBRiecl3s3nYFhj7zaALQ5",
+	"CvBzgws9n4pkGhqqU07O0gCskAZq47cN3aSBHW2sGRKe4Jn4A9IhBRNWIV/BTuX68+EAWnZSuTiJEYCs",
+	"CJt4/he3ibgdIWpdZC3O3b5kp0+P2Pfff/c9WxpSDBuTXa0KZ0+fnr46OSYXtHiH0SfOnpyfnfdPz5dC",
+	"6enp8b/+9u/j43+evP7p76+f9F//7fmv8fmr+N/H8fnP8dPTDrVgs8JgAIQ96T87eR0z90HMnv/64vzn",
+	"k9fY9+vj/unJ67rnZ1vn0c2tnNoWYYWYdybWGzvOCbfCMLjiWUGrK2SH/VoNbcjEpqZZzStWzYfxRCtj",
+	"0E2WicnUom2NdJRMuZyAqWPuwdzwt7GAHFWGSxUgtG4UeT3ZrkSHXsQFNtFdnDR1Pep7ahGsrs+WF677",
+	"pc+kHGcGqShmURxlXE/avSMBKK2BxhDH5dxouPDTixY0vWwLgPZpNz0CFNBIQla52OGvc9kWGBwJbadD",
+	"Ep7rtpNfGbd/UGNGrdGtycBYgTKSzUWWsVStsFcbSa7H+z408xgAvVZDIY3VRVKpm9tus3FpE54NpbJt",
+	"5tyRkqmgQWLmmuIfLmaUZaAnqLx4GXEyU1VkKbuUas74SBW0I9md/BSu3vAmUbGSYG8bTYRZDprPfH7E",
+	"is9wolQ6RH0yJDdPHDxIuG08mIos1SCjOOLynVCFccuJ8gSiOAKT8ByGqDuNRcYGSM1wVvzxR9bOUU2d",
+	"vo8taY1L1/EB+qOsomCGkN6upQ83WHVrWX3JeXFdpvnFC6QlcnqbAFhm17QaAT/8tfsD8+ksLAXLRWaY",
+	"+3xVEiQqhVZ3ncVNc8xmPJkKCQc4TXzCQGulGX7WCbZsQl7xTKRDv8uK4upJzpGeLOgojugJMczQZbRE",
+	"cVRIXtgpSIusBGnwZaIhxec8M07MjESaEj1JZYdjVVC0ZwZ2qlJk16FPYYniKFFynImEVBO3MKRsEN+5",
+	"BS151kphDlmtiIV3ecalY3aTQyLGInE7HXRU1JxSqPE9+lvZnTC4Zj8lZCquRFrwjI0FZKlxO0eHLbbE",
+	"387uTk8sT7GzVsNYGstlsk4PcDstp+SX1gGU8MJAunWq/pu1O6b/OvDpQQfPnjTGidFdyRKlNWSczGvy",
+	"DxvQV6AxE8p0oi0uhuZoP5+fv2SuQUXATXatuK+FJaZKW2aK2YzrRQltyWfYT2f9TmS1t1enz1iVCcIE",
+	"Ufp4Qbq7pdtKuhVaHFSfbQ2L0ttyShVyYsf1oaRxw22QNo6AVgKjQobqAckliqPfC9CLKg+NhlOXggSe",
+	"StsD4KUKbCNBLz8YtqlS5lJlLaQ1AiWGKTPMSmrFETs3Sk9ooNC3W0WWw0gLxs7AGL+FbzhyCjsdeqS0",
+	"qftA0a93RpW9tw1MRsdzbpNpK/3S69JRSpkwY9oC+qjWzXLHXJThoTLImmILs/qGa+0mn3o1LNO2NoXZ",
+	"ArAz4d02QNlc6yJVjM+UnFTNjEvBc2N2orYI2m4LG6xey+K+MqAbW6NdQuUwI40WRTWcBb/DSHXOjZkr",
+	"nbr2WmUufaDcR0Tx8ufFLkHrlS3YPgx+P6Pgc/ekpelmKtk17lYhJRiyetj8II7mWlhY9nhdIbLFnq5j",
+	"1lEAer3TmZC3iLnvffNfYjYkFzebQB4SZbZQrJCZkDAsM8OHj7vdBgFPuRnOlK5iMx4/b77kr3yW+Stf",
+	"lv1zXHaUJMrybOi5f9VxtxQTK/E9SR4Y8NskbMSoGxdqcnl/GZTZCmzkkvUFXEHq9hOpGJMxjR7ksQFL",
+	"TV3xQGV5mnatXknzXY2c1nztcOqtWczYgMliNiLDyk0vbiTAT9WczbhcuO1oKXGZkElWpGDaNjrXu0ns",
+	"4aNVO/bPsh5bUs0+oqWhtJKk0JjAivC7dfBbgXN1CbLcjC03XV4s9As7VVr80Yhd81z8ExaubknIscLv",
+	"q3zZCUh7pFj/5TN02oB225noUadLXskcJM9F1Iu+7XQ7Po93ShAdvlWjg1BOH74X6bXDXgbO24yURC+f",
+	"pVHPPx+ixOW1GPtytUnWo/+FXFVjnhmI3Vz9ftPP1OcglNaLMylW6nFCV8GCpmsE2SMoiGpVbI+7323P",
+	"nfD+Nec98jkN7YRX9R0UjcWRdydEvegJYYLVsWD5hFThL2pENte7AzPnkwnoA60KC/ogUdJqlWWgHXAE",
+	"SV7YVUw7Yf3xYJr2539Hh8D6orybFeM1WXq1Ju8V4cDV3LUvYoMCujcCbh+CB9fYD3hQvmgO2jIzpMv+",
+	"vdClx1kbvm5Hn9exkxOmypjJlWmJab1UlHWwkj/DRgvGnau7Ld7dYX0MsTNhBrKMu6OfA9bG7VHxeLdR",
+	"mX2AElPJeCBR/ZAaQwUWhPEpmIuZgoYp2RkgH9XZDedEzGbKgOc9kbwPqLrywgb5PrqfgZrEd+TwUi89",
+	"xQyPZY5bXabcQFZc352CkZCYhDmRxTL+fGfSrZTbBFrk7QTC9R+K9MOJ2juItFvTxK//3IPs+QfYYNHY",
+	"kzvJnUovNguU84wnpQPTDUThXMQLGCZsa65aWPCn9EC6mqzfMCP9t3oaiCsXnLuEEWMxcp5rlRb4DqWK",
+	"30UxIQeSQKHhy+QRoUPRa36i7pSdgnZfOwPKFQRLmA+kkmBCaVUHZlkz4PpWc0mNqTrbFQUMZLOY3jjb",
+	"/Lvuj1X+/ZwC26m3WWrJyyirB3Lq8htps0deexSWjJJnVE3iJlNIi4zypwq0/SmvW9IMB9LJfvD90H+m",
+	"VdoWHwOzxW27BKXFREiebU5UIhpLMU/9iWMZquKQrji+At1BRJCXQZbSoavVrFZ+vlvWUzkDbH2fdtkm",
+	"JfWgAum77o8PeQhEv8EZQItc556SN0KO6ezPcAsrgfi9qMCVXd96m67vhSoClVDCTCBcc5CsyBM1owT+",
+	"lZi6G9HbZD7JWAWirZk9vCqF/YhTbgaSZxp4ugjzkVEOmkuR5yg0j7EWpl4YwpMEcpKemqUgBaQobavi",
+	"qxaxhJ0vhlbVDcFPzwr4gBub8/perVrmfXAIgrto1e575RWzzUZsIYpGtjQ6wLwlUbmbNBinJ1w+d66E",
+	"tEyDLTSeEfOfPCvAYNbZFZTscwX6K8Nm/J2YFTOWY14TBTq//r6LmyqPzW+c9cCJD3bTPuSgq6mfamUe",
+	"dylqhSOGMasg4aKug1Z06NmlyGmSBpwjzTkUqfTPyzZa5B1BdW7FdlhDULu3ABUDa34FcNfqvaDe1Yf5",
+	"gV6UmfDolR3h9h8sapDvuagXqXqiSu5Efq6gbHOQ3hQbZERyyzLgqI4kLAPrwhAl7oiLKq4f4uKmObor",
+	"k32iJmc+D/KmM6tvBqqTmXwdwI6zKgMuYxcEvpsptx3m5dkmSq+ULuwIMoWT3Lf7h9iV/OHYTLWREtY0",
+	"VPaSKxB0GpSpVj6cYiUolWO44sFdBUYOsu1gpyDycIeJEGQShJ2CLo+gYZIy32awdZ/kM1Jq1s2yCLLD",
+	"QpLCNpKlt9tdCGuGHgK4GzJCOmxacKUoWNbI7so6wbZvB7NoVxbxdVwjH7wpDOgdAVoN7N4erPMqt80q",
+	"ZpS2pdqjdRwtdsWR0mvUXq1soxKjwbMguH0R3wp+AjsVGpIye3UXztMp6DUgc5MEwLq/cNgdALxPl1hb",
+	"zst9OcdOhLFBTNXbkbf0jCnTYp6WPux79F4/gN/6E/dY33Hv0RKBraPCxR3dCQS4AyeXm5oAaSRnsjnD",
+	"lY7mWC6EwUBLGZup+/vKkyFGdfekXetWJK/mQFYb7GAYcotYhX1h61TzuXTes85AEuxkxWw8LIWnqSHw",
+	"nUNEBe6ErwZyWaYaM6O8zU5nR+IcqpjPhIvWPf4ygv0ROflbItcvFDvyHPXwfrDy5IsalQRniCwpZR/7",
+	"+lMgh9rtmSfevF3/kwZz7j2Mc//xG0gFFS6V1TOlJHAnI62XFAPpRYU/mYnCPpWLwb3rec8ihGcshecq",
+	"DWR1sBJ7rqiUN4he10h/U1RlIKujKHfcB8RlwMSPBgO5Y8Dkw5LyA1oUnwajeJf93Xmlpv1b/fNrBVzY",
+	"cDhaDKtk2C9u6xu4rUsDcz/CEy2wmhN8rHTtRLp9Wv3O4vm8EsXuWWBsSUvbH7G4nrzauXsqYV2MzLA2",
+	"CULp0dTEFHtgtnkwkIZludnyaKC3atTzWQju6OKBdKdQJ6j/mLAxk4qVp4OXzjHSzJTsoEptt1i69oI9",
+	"xNgrT7PVi4ZRd/L9eKAHkgrYvHd06a2una8XVL5xdwZ8oSUGDs9WOqH8Mf9x8BlpdtrJxM43Z6fKgC8k",
+	"ww8H0lU6Tr5y3vFaM7IdpKIX4ZniLbq+zEQq1++jSI34jEJaD6LGwvLBPYZeiWaQiD1vxGxE23fagO9B",
+	"YD0VMq2ERVWOegdxZVxSfC0Hoc4O5NlcltHeVsE0jkcvqxI31g+2VH82Ev3vVROVk16fHfP4x+0L2bxT",
+	"5dZE4Csaot6bi5AkznB9mIeVfX2Cl558E1AE1hvuQBKuGdFEYUBvIAh8OnRt7sfeQFDu29FZjvHQns6P",
+	"gmROYSKMBc0QC6zvrtO5O8nsWr1CjT8h599+KlX2gusNnraPDKvde2fVvfnaaGWeSReFr1v/N12d1lKi",
+	"vPgYVuch5fQnsvjef3QPUvCQN26r2si2w1rrPxv/1i7UuU8+Dq7/wh1nHad35Og1TvTmmSjbbx2jrfNA",
+	"VpBSmoRxW3Iua1D/RBa+81S7VkFIcCDrTvF1XuuPiL72L4GapHX9wcn4AwQKcYXL8GvAAvvYbp7dD1c1",
+	"ZGXi7+s5GEN5x8dG+7FsP6T2n4cReQpX6hKq8/vxxgqPBeaxcGsBp8wW5yTe2kRVRkwchYOWTjoPEHo+",
+	"4+q6CCpGMuy3fxyfs3Cx8elvdHaU8WfWlV262z9N7YKogcQbosqroxKutT8wFBINllms5f8Ju8jcUXDC",
+	"uMyD0gvGeFXlVeVUqCyl3oxV+TL5b11N6sdIbPsTarWrsu5LN3sv+zraZZmQl/uWJR2RrPe891co2SXu",
+	"SAZXIG1Q/1wW1JGM9fmNSuN1QnXXeeyi3z928XRqw0Y8uaSSmiM6mzp1+ZHO8Z0Xo0yYKVbDuFHZVExQ",
+	"xZ8d/+vV8YujY3dbzDLd1V1CVF6FRr00+zg775+/Ousd9V8cHZ+cHD9Z3uy68MVTZF+ATCF17ERdYc23",
+	"ykGWSZvgGIoJV6WDTKI5mR92irYJ897CdV7zD88rcfvBIzipKj2Bpv7q9GRHzzZ9fTuodvVnW3hnK8qt",
+	"M2+j73YLgNC9bycRWtR7VzcNbt0QaV+vjsIPlsyJ+SIznkKMAn/pc3eRKsrxoGZ2CjMD2Zh4jA6Oc9eZ",
+	"ogoh/bEpIlSL9Y99vvKX+NCXkqdblTzV6Fj4y6bcEWq7l8C4A2pXy3pudinQaiXTErYzN8iHzgsfPmot",
+	"GCZLEmm9EcE3e/MplAM0jozxyhkZgRujEkFpr6SNa9UPtcBcvKu4XAboGqbzThLSWRvVhQYkG73xUhrB",
+	"yyZn5W3PLgvO3QbWIaeYqS5lHSk7ZXSUJZuApT9jVkg6tes3fP4bk1xrNTcuCk9IS+mOxVIAszvL3y8y",
+	"94vM3VuZKdfl6SKUxq90mXZ6q6q3DZLYF8S57m5TENQsAasq2ErHz6r80aqYeDmEzLnjJLBp6xRaz93d",
+	"okRQfpyiwPgoK4pqeuNedMUH0A852PX6AcufHNmUl59yR0CjBR0V3xDUlEyNez4U0TH6tQeyvJJ71vN3",
+	"uWSMrn1xSmV5FwpdHMpyLa64heVudiATrvWicYmqz9lCC8hY4Ok6+U9+bJrhnza19iXY1brxe62GKzd3",
+	"Hq/7dSH209S424Yb9ys5Siv/DAMgCEcQ9XB7NrOhEmqt264klz9TtIPo437TYvwQD54V8xFETRxt7uOY",
+	"lDR1ZL8vLwlyxeF7d6H1brWJOHon0ELBzSnWHZVGVz57MbyhTO+DslG80ygOL59oUtDd6GRLNtBnuXDd",
+	"+5aFe0xXuNva37K8bykaqgs3G/bYQJLOrt1njxcua3e1rbvTfmO+wWdCeA+o4j8Nsi4PP76b9qsHJZoH",
+	"t7+5QOQ7v4kjrEJnUS865LmgdfGjvi+Xu2SY6oHbPF1c/98AXpI2rpWTAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,98 @@
+package series
+
+import (
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/teambition/rrule-go"
+)
+
+// Recurrence expands the rule of a job series into the starts of its
+// occurrences.
+type Recurrence struct {
+	rule       *rrule.RRule
+	location   *time.Location
+	duration   time.Duration
+	exceptions map[string]bool
+}
+
+// NewRecurrence reads the rule, time zone and exceptions of series. It
+// returns a *domain.ValidationError when the rule or time zone is invalid.
+// The remaining fields are checked like those of a Job, by the caller.
+func NewRecurrence(series models.JobSeries) (*Recurrence, error) {
+	var v domain.ValidationError
+
+	location := time.UTC
+	switch loaded, err := time.LoadLocation(series.Timezone); {
+	case series.Timezone == "":
+		v.Add("timezone", "is required")
+	case err != nil || series.Timezone == "Local":
+		v.Add("timezone", "unknown time zone "+series.Timezone)
+	default:
+		location = loaded
+	}
+
+	option, err := rrule.StrToROptionInLocation(series.Rrule, location)
+	switch {
+	case series.Rrule == "":
+		v.Add("rrule", "is required")
+	case err != nil:
+		v.Add("rrule", err.Error())
+	case !option.Dtstart.IsZero():
+		v.Add("rrule", "must not contain DTSTART; starts_at is the first occurrence")
+	case option.Freq > rrule.DAILY:
+		v.Add("rrule", "FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	option.Dtstart = series.StartsAt.In(location)
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		v.Add("rrule", err.Error())
+		return nil, v.Err()
+	}
+
+	r := &Recurrence{
+		rule:       rule,
+		location:   location,
+		duration:   series.EndsAt.Sub(series.StartsAt),
+		exceptions: make(map[string]bool),
+	}
+	if series.Exceptions != nil {
+		for _, date := range *series.Exceptions {
+			r.exceptions[date.String()] = true
+		}
+	}
+
+	return r, nil
+}
+
+// Between returns the starts, in UTC, of the occurrences starting at or
+// after from and before to, leaving out those on exception dates.
+func (r *Recurrence) Between(from, to time.Time) []time.Time {
+	var starts []time.Time
+	for _, start := range r.rule.Between(from, to, true) {
+		if !start.Before(to) || r.exceptions[r.Date(start).String()] {
+			continue
+		}
+		starts = append(starts, start.UTC())
+	}
+
+	return starts
+}
+
+// Duration is how long every occurrence lasts.
+func (r *Recurrence) Duration() time.Duration {
+	return r.duration
+}
+
+// Date returns the local date of the occurrence starting at start, as
+// exceptions name it.
+func (r *Recurrence) Date(start time.Time) openapi_types.Date {
+	year, month, day := start.In(r.location).Date()
+	return openapi_types.Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
@@ -0,0 +1,194 @@
+package series
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func date(year int, month time.Month, day int) openapi_types.Date {
+	return openapi_types.Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func utc(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestRecurrenceBetween(t *testing.T) {
+	tests := []struct {
+		name       string
+		timezone   string
+		rrule      string
+		startsAt   time.Time
+		exceptions []openapi_types.Date
+		from       time.Time
+		to         time.Time
+		expected   []time.Time
+	}{
+		{
+			// Berlin moves its clocks forward on 2024-03-31; 09:00 local is
+			// 08:00 UTC before and 07:00 UTC after.
+			name:     "keeps local time into summer time",
+			timezone: "Europe/Berlin",
+			rrule:    "FREQ=DAILY",
+			startsAt: utc(2024, 3, 29, 8, 0),
+			from:     utc(2024, 3, 29, 0, 0),
+			to:       utc(2024, 4, 2, 0, 0),
+			expected: []time.Time{
+				utc(2024, 3, 29, 8, 0),
+				utc(2024, 3, 30, 8, 0),
+				utc(2024, 3, 31, 7, 0),
+				utc(2024, 4, 1, 7, 0),
+			},
+		},
+		{
+			name:     "keeps local time out of summer time",
+			timezone: "Europe/Berlin",
+			rrule:    "FREQ=WEEKLY;BYDAY=SA,SU",
+			startsAt: utc(2024, 10, 19, 7, 0),
+			from:     utc(2024, 10, 19, 0, 0),
+			to:       utc(2024, 11, 1, 0, 0),
+			expected: []time.Time{
+				utc(2024, 10, 19, 7, 0),
+				utc(2024, 10, 20, 7, 0),
+				utc(2024, 10, 26, 7, 0),
+				utc(2024, 10, 27, 8, 0),
+			},
+		},
+		{
+			name:       "skips an exception on the day clocks change",
+			timezone:   "Europe/Berlin",
+			rrule:      "FREQ=DAILY",
+			startsAt:   utc(2024, 3, 29, 8, 0),
+			exceptions: []openapi_types.Date{date(2024, 3, 31)},
+			from:       utc(2024, 3, 29, 0, 0),
+			to:         utc(2024, 4, 2, 0, 0),
+			expected: []time.Time{
+				utc(2024, 3, 29, 8, 0),
+				utc(2024, 3, 30, 8, 0),
+				utc(2024, 4, 1, 7, 0),
+			},
+		},
+		{
+			// 20:00 in New York is already the next day in UTC; exceptions
+			// name the local date.
+			name:       "exceptions are local dates",
+			timezone:   "America/New_York",
+			rrule:      "FREQ=DAILY;COUNT=3",
+			startsAt:   utc(2024, 3, 9, 1, 0),
+			exceptions: []openapi_types.Date{date(2024, 3, 9)},
+			from:       utc(2024, 3, 1, 0, 0),
+			to:         utc(2024, 4, 1, 0, 0),
+			expected: []time.Time{
+				utc(2024, 3, 9, 1, 0),
+				utc(2024, 3, 11, 0, 0),
+			},
+		},
+		{
+			name:     "from is inclusive and to exclusive",
+			timezone: "UTC",
+			rrule:    "FREQ=DAILY",
+			startsAt: utc(2024, 1, 1, 9, 0),
+			from:     utc(2024, 1, 2, 9, 0),
+			to:       utc(2024, 1, 4, 9, 0),
+			expected: []time.Time{
+				utc(2024, 1, 2, 9, 0),
+				utc(2024, 1, 3, 9, 0),
+			},
+		},
+		{
+			name:     "nothing before the first occurrence",
+			timezone: "UTC",
+			rrule:    "FREQ=MONTHLY",
+			startsAt: utc(2024, 5, 1, 9, 0),
+			from:     utc(2024, 1, 1, 0, 0),
+			to:       utc(2024, 5, 1, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := models.JobSeries{
+				Rrule:    tt.rrule,
+				Timezone: tt.timezone,
+				StartsAt: tt.startsAt,
+				EndsAt:   tt.startsAt.Add(time.Hour),
+			}
+			if tt.exceptions != nil {
+				series.Exceptions = &tt.exceptions
+			}
+
+			r, err := NewRecurrence(series)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r.Duration() != time.Hour {
+				t.Fatalf("expected every occurrence to last an hour, got %s", r.Duration())
+			}
+
+			starts := r.Between(tt.from, tt.to)
+			if len(starts) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, starts)
+			}
+			for i, start := range starts {
+				if !start.Equal(tt.expected[i]) || start.Location() != time.UTC {
+					t.Fatalf("expected %v, got %v", tt.expected, starts)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRecurrenceValidation(t *testing.T) {
+	startsAt := utc(2024, 1, 1, 9, 0)
+
+	tests := []struct {
+		name     string
+		rrule    string
+		timezone string
+		fields   []string
+	}{
+		{name: "valid", rrule: "FREQ=WEEKLY;BYDAY=MO", timezone: "Europe/London"},
+		{name: "missing everything", fields: []string{"timezone", "rrule"}},
+		{name: "unknown time zone", rrule: "FREQ=DAILY", timezone: "Mars/Olympus", fields: []string{"timezone"}},
+		{name: "local time zone", rrule: "FREQ=DAILY", timezone: "Local", fields: []string{"timezone"}},
+		{name: "unparsable rule", rrule: "FREQ=SOMETIMES", timezone: "UTC", fields: []string{"rrule"}},
+		{name: "rule with DTSTART", rrule: "DTSTART:20240101T090000Z\nFREQ=DAILY", timezone: "UTC", fields: []string{"rrule"}},
+		{name: "hourly rule", rrule: "FREQ=HOURLY", timezone: "UTC", fields: []string{"rrule"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRecurrence(models.JobSeries{
+				Rrule:    tt.rrule,
+				Timezone: tt.timezone,
+				StartsAt: startsAt,
+				EndsAt:   startsAt.Add(time.Hour),
+			})
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validation *domain.ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("expected a *domain.ValidationError, got %v", err)
+			}
+			if len(validation.Violations) != len(tt.fields) {
+				t.Fatalf("expected violations of %v, got %+v", tt.fields, validation.Violations)
+			}
+			for i, v := range validation.Violations {
+				if v.Field != tt.fields[i] {
+					t.Fatalf("expected violations of %v, got %+v", tt.fields, validation.Violations)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+// Package series keeps the occurrences of recurring job series in step with
+// their rules. Every occurrence is an ordinary Job carrying the id of its
+// series, created a rolling horizon ahead of time, so that everything that
+// works with jobs, from applications to schedules, works with occurrences.
+package series
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/logging"
+)
+
+type Service struct {
+	series       domain.JobSeriesRepository
+	jobs         domain.JobRepository
+	applications domain.JobApplicationRepository
+	horizon      time.Duration
+	buffer       time.Duration
+}
+
+// New returns a Service creating the occurrences that start within horizon
+// from now. Occurrences with a worker are only moved to where they stay
+// buffer away from the worker's other jobs.
+func New(series domain.JobSeriesRepository, jobs domain.JobRepository, applications domain.JobApplicationRepository, horizon, buffer time.Duration) *Service {
+	return &Service{
+		series:       series,
+		jobs:         jobs,
+		applications: applications,
+		horizon:      horizon,
+		buffer:       buffer,
+	}
+}
+
+// Create stores a new series and creates its first occurrences.
+func (s *Service) Create(ctx context.Context, series models.JobSeries) (models.JobSeries, error) {
+	if _, err := NewRecurrence(series); err != nil {
+		return models.JobSeries{}, err
+	}
+
+	created, err := s.series.PostJobSeries(ctx, series)
+	if err != nil {
+		return models.JobSeries{}, err
+	}
+
+	return s.Materialize(ctx, created)
+}
+
+func (s *Service) Get(ctx context.Context, id string) (models.JobSeries, error) {
+	return s.series.GetJobSeries(ctx, id)
+}
+
+// Materialize creates the occurrences of series that start within the
+// horizon and have not been created yet, applying the series' applicants to
+// each. Occurrences that have already started are skipped.
+func (s *Service) Materialize(ctx context.Context, series models.JobSeries) (models.JobSeries, error) {
+	from := time.Now()
+	until := from.Add(s.horizon)
+	if series.MaterializedUntil != nil && series.MaterializedUntil.After(from) {
+		from = *series.MaterializedUntil
+	}
+	if !from.Before(until) {
+		return series, nil
+	}
+
+	recurrence, err := NewRecurrence(series)
+	if err != nil {
+		return models.JobSeries{}, fmt.Errorf("job series %s: %w", *series.Id, err)
+	}
+	for _, start := range recurrence.Between(from, until) {
+		if err := s.createOccurrence(ctx, series, start, recurrence.Duration()); err != nil {
+			return models.JobSeries{}, err
+		}
+	}
+
+	if err := s.series.SetJobSeriesMaterializedUntil(ctx, *series.Id, until); err != nil {
+		return models.JobSeries{}, err
+	}
+
+	return s.series.GetJobSeries(ctx, *series.Id)
+}
+
+// Edit replaces the series id with edit and applies it to the occurrences
+// whose RecurrenceId is at or after from; occurrences that have already
+// started are never changed. Occurrences the new rule still produces are
+// updated in place, the others are deleted and the missing ones created.
+// Detached occurrences are left alone. Editing away an occurrence that has a
+// worker is an ErrConflict, and moving one to within the scheduling buffer of
+// another job of the worker a *domain.ScheduleConflictError, both checked
+// before anything is changed.
+//
+// The steps are not atomic. An edit that fails part-way can be retried,
+// since it only ever brings the occurrences in line with the series.
+func (s *Service) Edit(ctx context.Context, id string, edit models.JobSeries, from time.Time) (models.JobSeries, error) {
+	current, err := s.series.GetJobSeries(ctx, id)
+	if err != nil {
+		return models.JobSeries{}, err
+	}
+	recurrence, err := NewRecurrence(edit)
+	if err != nil {
+		return models.JobSeries{}, err
+	}
+
+	now := time.Now()
+	if from.Before(now) {
+		from = now
+	}
+	until := now.Add(s.horizon)
+	if current.MaterializedUntil != nil && current.MaterializedUntil.After(until) {
+		until = *current.MaterializedUntil
+	}
+
+	existing, _, err := s.jobs.GetJobs(ctx, domain.JobQuery{SeriesId: &id, RecurringAfter: &from})
+	if err != nil {
+		return models.JobSeries{}, err
+	}
+
+	starts := recurrence.Between(from, until)
+	wanted := make(map[int64]bool, len(starts))
+	for _, start := range starts {
+		wanted[start.UnixMilli()] = true
+	}
+
+	occurrences := make(map[int64]models.Job, len(existing))
+	var stale []models.Job
+	for _, job := range existing {
+		key := job.RecurrenceId.UnixMilli()
+		switch {
+		case job.Detached != nil && *job.Detached:
+			occurrences[key] = job
+		case wanted[key]:
+			occurrences[key] = job
+		case job.WorkerUserId != nil:
+			return models.JobSeries{}, fmt.Errorf("the edit would remove occurrence %s, which has a worker; withdraw the accepted application first: %w", *job.Id, domain.ErrConflict)
+		default:
+			stale = append(stale, job)
+		}
+	}
+
+	for _, start := range starts {
+		job, ok := occurrences[start.UnixMilli()]
+		if !ok || job.WorkerUserId == nil || job.Detached != nil && *job.Detached {
+			continue
+		}
+		moved := domain.Interval{Start: start, End: start.Add(recurrence.Duration())}
+		if err := s.checkSchedule(ctx, job, moved); err != nil {
+			return models.JobSeries{}, err
+		}
+	}
+
+	updated, err := s.series.PutJobSeries(ctx, id, edit)
+	if err != nil {
+		return models.JobSeries{}, err
+	}
+
+	for _, job := range stale {
+		if err := s.applications.DeleteJob(ctx, *job.Id, nil); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return models.JobSeries{}, err
+		}
+	}
+
+	for _, start := range starts {
+		job, ok := occurrences[start.UnixMilli()]
+		if !ok {
+			if err := s.createOccurrence(ctx, updated, start, recurrence.Duration()); err != nil {
+				return models.JobSeries{}, err
+			}
+			continue
+		}
+		if job.Detached != nil && *job.Detached {
+			continue
+		}
+
+		_, err := s.jobs.UpdateOccurrence(ctx, *job.Id, occurrence(updated, start, recurrence.Duration()), s.buffer)
+		var conflict *domain.ScheduleConflictError
+		if errors.As(err, &conflict) {
+			return models.JobSeries{}, err
+		}
+		if err != nil && !errors.Is(err, domain.ErrConflict) && !errors.Is(err, domain.ErrNotFound) {
+			return models.JobSeries{}, err
+		}
+	}
+
+	if err := s.series.SetJobSeriesMaterializedUntil(ctx, id, until); err != nil {
+		return models.JobSeries{}, err
+	}
+
+	return s.series.GetJobSeries(ctx, id)
+}
+
+// Apply applies userId to every open occurrence of the series that has not
+// started yet, and records them as an applicant of the series so that later
+// occurrences get an application too. Occurrences userId already applied to
+// are skipped. It returns the applications created.
+func (s *Service) Apply(ctx context.Context, id string, userId string) ([]models.JobApplication, error) {
+	if err := s.series.AddJobSeriesApplicant(ctx, id, userId); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	open := true
+	jobs, _, err := s.jobs.GetJobs(ctx, domain.JobQuery{SeriesId: &id, Open: &open, StartsAfter: &now})
+	if err != nil {
+		return nil, err
+	}
+
+	applications := []models.JobApplication{}
+	for _, job := range jobs {
+		application, err := s.applications.CreateJobApplication(ctx, models.JobApplication{
+			JobId:  job.Id,
+			UserId: &userId,
+		})
+		if errors.Is(err, domain.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, application)
+	}
+
+	return applications, nil
+}
+
+// Skip adds the date of the occurrence job to its series' exceptions, so
+// that it is not created again once deleted. It does nothing for jobs that
+// are not occurrences.
+func (s *Service) Skip(ctx context.Context, job models.Job) error {
+	if job.SeriesId == nil || job.RecurrenceId == nil {
+		return nil
+	}
+
+	series, err := s.series.GetJobSeries(ctx, *job.SeriesId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	recurrence, err := NewRecurrence(series)
+	if err != nil {
+		return fmt.Errorf("job series %s: %w", *series.Id, err)
+	}
+
+	return s.series.AddJobSeriesException(ctx, *job.SeriesId, recurrence.Date(*job.RecurrenceId))
+}
+
+// Run materializes every series due for it each interval until ctx is done.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.materializeDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) materializeDue(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	due, err := s.series.GetJobSeriesDue(ctx, time.Now().Add(s.horizon))
+	if err != nil {
+		logger.Error("Finding job series to materialize", slog.Any("error", err))
+		return
+	}
+
+	for _, series := range due {
+		if _, err := s.Materialize(ctx, series); err != nil {
+			logger.Error("Materializing job series", slog.String("series_id", *series.Id), slog.Any("error", err))
+		}
+	}
+}
+
+// createOccurrence creates the occurrence of series starting at start and
+// applies the series' applicants to it. An occurrence that already exists,
+// created concurrently by another instance, is left as it is.
+func (s *Service) createOccurrence(ctx context.Context, series models.JobSeries, start time.Time, duration time.Duration) error {
+	job, err := s.jobs.PostJobs(ctx, occurrence(series, start, duration))
+	if errors.Is(err, domain.ErrConflict) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if series.ApplicantUserIds == nil {
+		return nil
+	}
+	for _, userId := range *series.ApplicantUserIds {
+		userId := userId
+		_, err := s.applications.CreateJobApplication(ctx, models.JobApplication{
+			JobId:  job.Id,
+			UserId: &userId,
+		})
+		if err != nil && !errors.Is(err, domain.ErrConflict) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkSchedule fails with a *domain.ScheduleConflictError when job has
+// moved to moved and comes within the buffer of another job its worker
+// works. UpdateOccurrence checks again under the worker's schedule, this only
+// keeps an edit that is bound to fail from changing anything.
+func (s *Service) checkSchedule(ctx context.Context, job models.Job, moved domain.Interval) error {
+	if moved.Start.Equal(job.StartsAt) && moved.End.Equal(job.EndsAt) {
+		return nil
+	}
+
+	window := moved.Pad(s.buffer)
+	working, _, err := s.jobs.GetJobs(ctx, domain.JobQuery{
+		WorkerUserId: job.WorkerUserId,
+		StartsBefore: &window.End,
+		EndsAfter:    &window.Start,
+	})
+	if err != nil {
+		return err
+	}
+	for _, other := range working {
+		if *other.Id != *job.Id {
+			return &domain.ScheduleConflictError{JobId: *other.Id}
+		}
+	}
+
+	return nil
+}
+
+// occurrence returns the job series describes for the occurrence starting at
+// start.
+func occurrence(series models.JobSeries, start time.Time, duration time.Duration) models.Job {
+	recurrenceId := start
+	job := models.Job{
+		CreatorUserId: series.CreatorUserId,
+		Description:   series.Description,
+		Dog:           series.Dog,
+		Activities:    series.Activities,
+		StartsAt:      start,
+		EndsAt:        start.Add(duration),
+		SeriesId:      series.Id,
+		RecurrenceId:  &recurrenceId,
+	}
+	if series.Dog != nil {
+		job.Dogs = &[]models.JobDog{*series.Dog}
+	}
+
+	return job
+}
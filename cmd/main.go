@@ -1,29 +1,412 @@
 package main
 
 import (
-	"log"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/netip"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 
+	"github.com/bersennaidoo/agentco/application/auth"
+	"github.com/bersennaidoo/agentco/application/calendar"
+	"github.com/bersennaidoo/agentco/application/matching"
+	"github.com/bersennaidoo/agentco/application/metrics"
+	"github.com/bersennaidoo/agentco/application/ratelimit"
 	"github.com/bersennaidoo/agentco/application/rest/handlers"
+	"github.com/bersennaidoo/agentco/application/rest/health"
+	"github.com/bersennaidoo/agentco/application/rest/middleware"
+	"github.com/bersennaidoo/agentco/application/rest/problem"
 	"github.com/bersennaidoo/agentco/application/rest/server"
+	"github.com/bersennaidoo/agentco/application/series"
 	"github.com/bersennaidoo/agentco/infrastructure/repositories/mongo"
+	"github.com/bersennaidoo/agentco/physical/certs"
 	"github.com/bersennaidoo/agentco/physical/config"
 	"github.com/bersennaidoo/agentco/physical/dbc"
+	"github.com/bersennaidoo/agentco/physical/logging"
+	"github.com/bersennaidoo/agentco/physical/tracing"
+	"github.com/gorilla/mux"
+	"github.com/spf13/pflag"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 func main() {
-	config := config.New(config.GetConfigFileName())
-	mclient := dbc.New(config)
+	config, opts, err := config.Load(os.Args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if opts.PrintConfig {
+		fmt.Print(config)
+		return
+	}
 
-	usrepo := mongo.NewUserRepository(mclient)
-	hnd := handlers.New(usrepo)
-	sgorptions := server.GorillaServerOptions{}
+	logger, err := logging.New(config.Logging, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Tracing)
+	if err != nil {
+		fatal(err)
+	}
+
+	monitors := []*event.CommandMonitor{otelmongo.NewMonitor()}
+	var meters *metrics.Metrics
+	if config.Metrics.Enabled {
+		meters = metrics.New()
+		monitors = append(monitors, meters.CommandMonitor())
+	}
+
+	mclient, err := dbc.New(config.Database, monitors...)
+	if err != nil {
+		fatal(err)
+	}
+	db := mclient.Database(config.Database.DatabaseName)
+
+	usrepo := mongo.NewUserRepository(db)
+	if err := usrepo.EnsureIndexes(context.Background()); err != nil {
+		fatal(err)
+	}
+
+	sesrepo := mongo.NewSessionRepository(db)
+	if err := sesrepo.EnsureIndexes(context.Background()); err != nil {
+		fatal(err)
+	}
+
+	jobrepo := mongo.NewJobRepository(db)
+	if err := jobrepo.EnsureIndexes(context.Background()); err != nil {
+		fatal(err)
+	}
+
+	apprepo := mongo.NewJobApplicationRepository(db)
+	if err := apprepo.EnsureIndexes(context.Background()); err != nil {
+		fatal(err)
+	}
+
+	seriesrepo := mongo.NewJobSeriesRepository(db)
+	if err := seriesrepo.EnsureIndexes(context.Background()); err != nil {
+		fatal(err)
+	}
+	recurring := series.New(seriesrepo, jobrepo, apprepo, config.Series.Horizon, config.Scheduling.Buffer)
+
+	availrepo := mongo.NewAvailabilityRepository(db)
+	if err := availrepo.EnsureIndexes(context.Background()); err != nil {
+		fatal(err)
+	}
+	matcher := matching.New(availrepo, usrepo, jobrepo, config.Scheduling.Buffer)
+
+	petrepo := mongo.NewPetRepository(db)
+	if err := petrepo.EnsureIndexes(context.Background()); err != nil {
+		fatal(err)
+	}
+
+	feedrepo := mongo.NewCalendarFeedRepository(db)
+	if err := feedrepo.EnsureIndexes(context.Background()); err != nil {
+		fatal(err)
+	}
+	feeds := calendar.New(feedrepo)
+
+	credentials := auth.NewCredentials(auth.PasswordParams{
+		Memory:      config.Password.Argon2Memory,
+		Iterations:  config.Password.Argon2Iterations,
+		Parallelism: config.Password.Argon2Parallelism,
+		SaltLength:  config.Password.Argon2SaltLength,
+		KeyLength:   config.Password.Argon2KeyLength,
+	}, auth.PasswordPolicy{
+		MinLength:     config.Password.MinLength,
+		MaxLength:     config.Password.MaxLength,
+		RequireUpper:  config.Password.RequireUpper,
+		RequireLower:  config.Password.RequireLower,
+		RequireDigit:  config.Password.RequireDigit,
+		RequireSymbol: config.Password.RequireSymbol,
+	}, usrepo)
+	sessions, err := auth.NewSessions(config.Auth.TokenSecret, config.Auth.SessionTTL, sesrepo, usrepo)
+	if err != nil {
+		fatal(err)
+	}
+	authorizer := auth.NewAuthorizer(auth.Rules)
+	authorizer.RegisterOwner(auth.JobCreator, func(ctx context.Context, id string) (string, error) {
+		job, err := jobrepo.GetJobsId(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return *job.CreatorUserId, nil
+	})
+	authorizer.RegisterOwner(auth.Applicant, func(ctx context.Context, id string) (string, error) {
+		application, err := apprepo.GetJobApplication(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return *application.UserId, nil
+	})
+	authorizer.RegisterOwner(auth.ApplicationJobCreator, func(ctx context.Context, id string) (string, error) {
+		application, err := apprepo.GetJobApplication(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		job, err := jobrepo.GetJobsId(ctx, *application.JobId)
+		if err != nil {
+			return "", err
+		}
+		return *job.CreatorUserId, nil
+	})
+	authorizer.RegisterOwner(auth.JobSeriesCreator, func(ctx context.Context, id string) (string, error) {
+		found, err := seriesrepo.GetJobSeries(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return *found.CreatorUserId, nil
+	})
+
+	pagination := handlers.Pagination{
+		DefaultLimit: config.Pagination.DefaultLimit,
+		MaxLimit:     config.Pagination.MaxLimit,
+	}
+	schedule := handlers.Schedule{Buffer: config.Scheduling.Buffer}
+	links := handlers.Links{BaseURL: config.HTTP.PublicURL}
+
+	validate, err := middleware.Validate(middleware.ValidationOptions{
+		ValidateResponses: config.HTTP.ValidateResponses,
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	checker := health.New()
+	checker.Register("mongo", config.Health.CheckTimeout, func(ctx context.Context) error {
+		return mclient.Ping(ctx, readpref.Primary())
+	})
+
+	limiter, trustedProxies, lockout := newRateLimits(config.RateLimit)
+
+	hnd := handlers.New(usrepo, jobrepo, apprepo, availrepo, petrepo, recurring, matcher, feeds, credentials, sessions, pagination, schedule, links, meters, lockout)
+	baseRouter := mux.NewRouter()
+	baseRouter.NotFoundHandler = http.HandlerFunc(problem.RouteNotFound)
+	baseRouter.MethodNotAllowedHandler = problem.MethodNotAllowed(func(r *http.Request) []string {
+		return middleware.AllowedMethods(baseRouter, r)
+	})
+	// The probes are not part of the API: they are registered ahead of the
+	// generated routes and skip authentication and validation.
+	baseRouter.HandleFunc("/healthz", checker.Live).Methods(http.MethodGet, http.MethodHead)
+	baseRouter.HandleFunc("/readyz", checker.Ready).Methods(http.MethodGet, http.MethodHead)
+	// The generated wrapper wraps the handler with each middleware in turn,
+	// so the last one listed runs first.
+	middlewares := []server.MiddlewareFunc{
+		middleware.Authorize(authorizer),
+		validate,
+	}
+	if limiter != nil {
+		middlewares = append(middlewares, middleware.RateLimit(limiter, trustedProxies))
+	}
+	middlewares = append(middlewares, middleware.Authenticate(sessions))
+	if limiter != nil {
+		middlewares = append(middlewares, middleware.RateLimitIP(limiter, trustedProxies))
+	}
+	middlewares = append(middlewares, middleware.Trace())
+	sgorptions := server.GorillaServerOptions{
+		BaseRouter:       baseRouter,
+		Middlewares:      middlewares,
+		ErrorHandlerFunc: problem.Error,
+	}
 	router := server.HandlerWithOptions(hnd, sgorptions)
 
-	addr := config.GetString("http.http_addr")
+	handler := router
+	if meters != nil {
+		handler = middleware.Metrics(meters, baseRouter)(handler)
+	}
+	if len(config.CORS.AllowedOrigins) > 0 {
+		handler = middleware.CORS(middleware.CORSOptions{
+			AllowedOrigins:   config.CORS.AllowedOrigins,
+			AllowedMethods:   config.CORS.AllowedMethods,
+			AllowedHeaders:   config.CORS.AllowedHeaders,
+			ExposedHeaders:   config.CORS.ExposedHeaders,
+			AllowCredentials: config.CORS.AllowCredentials,
+			MaxAge:           config.CORS.MaxAge,
+		}, baseRouter)(handler)
+	}
+	handler = middleware.RequestLog(logger, baseRouter)(handler)
+	newServer := func(addr string, handler http.Handler) *http.Server {
+		return &http.Server{
+			Addr:         addr,
+			Handler:      handler,
+			ReadTimeout:  config.HTTP.ReadTimeout,
+			WriteTimeout: config.HTTP.WriteTimeout,
+			IdleTimeout:  config.HTTP.IdleTimeout,
+			ErrorLog:     slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go recurring.Run(ctx, config.Series.MaterializeInterval)
+
+	var servers []*http.Server
+	serverErr := make(chan error, 3)
+
+	if meters != nil {
+		metricsRouter := mux.NewRouter()
+		metricsRouter.Handle("/metrics", meters.Handler()).Methods(http.MethodGet)
+		srv := newServer(config.Metrics.Addr, metricsRouter)
+		servers = append(servers, srv)
+		go func() {
+			logger.Info("Metrics server starting", slog.String("addr", srv.Addr))
+			serverErr <- srv.ListenAndServe()
+		}()
+	}
+
+	tlsConfig, err := newTLSConfig(ctx, config.HTTPS)
+	if err != nil {
+		fatal(err)
+	}
+
+	httpHandler := handler
+	if tlsConfig != nil {
+		srv := newServer(config.HTTPS.Addr, handler)
+		srv.TLSConfig = tlsConfig
+		servers = append(servers, srv)
+		go func() {
+			logger.Info("HTTPS server starting", slog.String("addr", srv.Addr))
+			serverErr <- srv.ListenAndServeTLS("", "")
+		}()
+
+		if config.HTTPS.RedirectHTTP {
+			httpHandler = middleware.RedirectHTTPS(srv.Addr, handler)
+		}
+	}
+
+	srv := newServer(config.HTTP.Addr, httpHandler)
+	servers = append(servers, srv)
+	go func() {
+		logger.Info("Server starting", slog.String("addr", srv.Addr))
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Server failed", slog.Any("error", err))
+		}
+	case <-ctx.Done():
+		logger.Info("Shutting down")
+		// A second signal now ends the process without waiting.
+		stop()
+		checker.Drain()
+		time.Sleep(config.Health.DrainDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.HTTP.ShutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, srv := range servers {
+		wg.Add(1)
+		go func(srv *http.Server) {
+			defer wg.Done()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				logger.Error("Error while draining requests", slog.String("addr", srv.Addr), slog.Any("error", err))
+			}
+		}(srv)
+	}
+	wg.Wait()
+
+	if err := mclient.Disconnect(shutdownCtx); err != nil {
+		logger.Error("Error while disconnecting from MongoDB", slog.Any("error", err))
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Error while flushing traces", slog.Any("error", err))
+	}
+
+	logger.Info("Server stopped")
+}
+
+// newTLSConfig returns the TLS configuration for the HTTPS server, or nil when
+// HTTPS is not configured. Certificates read from disk are reloaded when the
+// files change until ctx is done.
+func newTLSConfig(ctx context.Context, config config.HTTPS) (*tls.Config, error) {
+	if !config.Enabled() {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.SelfSigned {
+		cert, err := certs.SelfSigned(config.SelfSignedHosts, 30*24*time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		slog.Warn("Serving a self-signed certificate; do not use this in production", slog.Any("hosts", config.SelfSignedHosts))
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		return tlsConfig, nil
+	}
+
+	reloader, err := certs.NewReloader(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := reloader.Watch(ctx); err != nil {
+			slog.Warn("TLS certificate will not be reloaded", slog.Any("error", err))
+		}
+	}()
+
+	tlsConfig.GetCertificate = reloader.GetCertificate
+	return tlsConfig, nil
+}
+
+// fatal logs err and exits, for errors the server cannot start with.
+func fatal(err error) {
+	slog.Error("Cannot start the server", slog.Any("error", err))
+	os.Exit(1)
+}
+
+// newRateLimits builds the request limiter, nil when rate limiting is
+// disabled, and the login lockout from config, which has been validated.
+func newRateLimits(config config.RateLimit) (*ratelimit.Limiter, []netip.Prefix, *ratelimit.Lockout) {
+	store := ratelimit.NewMemoryStore()
+
+	var limiter *ratelimit.Limiter
+	if config.Enabled {
+		limiter = &ratelimit.Limiter{
+			Store:      store,
+			Operations: make(map[string]ratelimit.Limit),
+		}
+		limiter.Default, _ = ratelimit.ParseLimit(config.Default)
+		limiter.PerIP, _ = ratelimit.ParseLimit(config.PerIP)
+		for operation, limit := range config.Operations {
+			limiter.Operations[strings.ToLower(operation)], _ = ratelimit.ParseLimit(limit)
+		}
+	}
+
+	var trustedProxies []netip.Prefix
+	for _, proxy := range config.TrustedProxies {
+		trustedProxies = append(trustedProxies, netip.MustParsePrefix(proxy))
+	}
 
-	log.Println("Server starting :3000")
-	err := http.ListenAndServe(addr, router)
-	log.Fatal(err)
+	lockout := &ratelimit.Lockout{
+		Store: store,
+		Backoff: ratelimit.Backoff{
+			Threshold: config.LockoutThreshold,
+			Base:      config.LockoutBase,
+			Max:       config.LockoutMax,
+			Forget:    config.LockoutForget,
+		},
+	}
 
+	return limiter, trustedProxies, lockout
 }
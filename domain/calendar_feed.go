@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// CalendarFeed is the iCalendar feed of a user's jobs. It is opened by a
+// token, of which only the hash is kept, and remembers the events it has
+// published so that changed jobs are republished with a higher SEQUENCE and
+// jobs that leave the feed are published as cancelled.
+type CalendarFeed struct {
+	UserId    string
+	TokenHash string
+	Events    []CalendarEvent
+	// Version counts the times Events has been replaced, so that two
+	// fetches publishing at once cannot both replace the same events.
+	Version int
+}
+
+// CalendarEvent is the last published state of one job in a feed.
+type CalendarEvent struct {
+	JobId    string
+	Sequence int
+	// Fingerprint identifies the published content; a job whose content
+	// no longer matches it is republished.
+	Fingerprint string
+	Summary     string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Cancelled   bool
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrForbidden  = errors.New("forbidden")
+	ErrValidation = errors.New("validation failed")
+)
+
+// Violation is one rule some input breaks, tied to the field it concerns.
+type Violation struct {
+	Field  string
+	Reason string
+}
+
+// ValidationError collects every rule some input breaks. It matches
+// ErrValidation.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Add records that field breaks a rule, described by reason.
+func (e *ValidationError) Add(field, reason string) {
+	e.Violations = append(e.Violations, Violation{Field: field, Reason: reason})
+}
+
+// Err returns e when any violation has been added, and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Field+" "+v.Reason)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
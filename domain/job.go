@@ -0,0 +1,16 @@
+package domain
+
+import "github.com/bersennaidoo/agentco/domain/models"
+
+// JobDogs returns every dog of job. Jobs saved before dogs was added only
+// have dog.
+func JobDogs(job models.Job) []models.JobDog {
+	if job.Dogs != nil {
+		return *job.Dogs
+	}
+	if job.Dog != nil {
+		return []models.JobDog{*job.Dog}
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package domain
+
+// A job application moves through these states:
+//
+//	APPLYING -> ACCEPTED  the job creator accepts it. The job's worker is set
+//	                      and every other pending application is denied.
+//	APPLYING -> DENIED    the job creator denies it.
+//
+// At any point the applicant may withdraw, which deletes the application.
+// Withdrawing an accepted application reopens the job. Any other transition
+// is rejected with ErrConflict.
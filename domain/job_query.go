@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+// JobQuery selects, orders and pages jobs. Filters left at their zero value
+// are not applied.
+type JobQuery struct {
+	// Activities a job must all include.
+	Activities  []models.JobActivities
+	DogSize     *models.JobDogSize
+	StartsAfter *time.Time
+	EndsBefore  *time.Time
+	// StartsBefore and EndsAfter are exclusive, so that together they match
+	// the jobs overlapping the interval between them.
+	StartsBefore  *time.Time
+	EndsAfter     *time.Time
+	Open          *bool
+	CreatorUserId *string
+	WorkerUserId  *string
+	// WorkerUserIds matches jobs worked by any of these users.
+	WorkerUserIds []string
+	// ParticipantUserId matches jobs the user either posted or works on.
+	ParticipantUserId *string
+	SeriesId          *string
+	// RecurringAfter matches occurrences of a series whose RecurrenceId is
+	// at or after it.
+	RecurringAfter *time.Time
+	// Avoid drops jobs overlapping any of these intervals.
+	Avoid []Interval
+
+	// SortBy defaults to models.StartsAt. Ties are broken by id.
+	SortBy     models.GetJobsParamsSort
+	Descending bool
+	Limit      int
+	Offset     int
+}
+
+// JobApplicationQuery selects and pages the applications a user has made,
+// newest first.
+type JobApplicationQuery struct {
+	UserId string
+	Status *models.JobApplicationStatus
+	Limit  int
+	Offset int
+}
@@ -13,6 +13,35 @@ const (
 	SessionTokenScopes = "SessionToken.Scopes"
 )
 
+// Defines values for AvailabilitySlotDay.
+const (
+	Friday    AvailabilitySlotDay = "friday"
+	Monday    AvailabilitySlotDay = "monday"
+	Saturday  AvailabilitySlotDay = "saturday"
+	Sunday    AvailabilitySlotDay = "sunday"
+	Thursday  AvailabilitySlotDay = "thursday"
+	Tuesday   AvailabilitySlotDay = "tuesday"
+	Wednesday AvailabilitySlotDay = "wednesday"
+)
+
+// Defines values for GetJobsParamsOrder.
+const (
+	Asc  GetJobsParamsOrder = "asc"
+	Desc GetJobsParamsOrder = "desc"
+)
+
+// Defines values for GetJobsParamsSort.
+const (
+	CreatedAt GetJobsParamsSort = "created_at"
+	StartsAt  GetJobsParamsSort = "starts_at"
+)
+
+// Defines values for GetJobsForUserParamsStatus.
+const (
+	Filled GetJobsForUserParamsStatus = "filled"
+	Open   GetJobsForUserParamsStatus = "open"
+)
+
 // Defines values for JobActivities.
 const (
 	Boarding JobActivities = "boarding"
@@ -36,6 +65,41 @@ const (
 	Small  JobDogSize = "small"
 )
 
+// Defines values for PetTemperament.
+const (
+	Anxious          PetTemperament = "anxious"
+	EscapeArtist     PetTemperament = "escape_artist"
+	GoodWithCats     PetTemperament = "good_with_cats"
+	GoodWithChildren PetTemperament = "good_with_children"
+	GoodWithDogs     PetTemperament = "good_with_dogs"
+	NeedsMuzzle      PetTemperament = "needs_muzzle"
+	Reactive         PetTemperament = "reactive"
+)
+
+// Defines values for ProblemCode.
+const (
+	Conflict           ProblemCode = "conflict"
+	Forbidden          ProblemCode = "forbidden"
+	Internal           ProblemCode = "internal"
+	InvalidCredentials ProblemCode = "invalid_credentials"
+	InvalidParameter   ProblemCode = "invalid_parameter"
+	InvalidRequest     ProblemCode = "invalid_request"
+	MethodNotAllowed   ProblemCode = "method_not_allowed"
+	NotFound           ProblemCode = "not_found"
+	RateLimited        ProblemCode = "rate_limited"
+	Unauthenticated    ProblemCode = "unauthenticated"
+	ValidationFailed   ProblemCode = "validation_failed"
+)
+
+// Defines values for ProblemFieldIn.
+const (
+	Body   ProblemFieldIn = "body"
+	Cookie ProblemFieldIn = "cookie"
+	Header ProblemFieldIn = "header"
+	Path   ProblemFieldIn = "path"
+	Query  ProblemFieldIn = "query"
+)
+
 // Defines values for UserRoles.
 const (
 	Admin     UserRoles = "Admin"
@@ -43,6 +107,53 @@ const (
 	PetSitter UserRoles = "PetSitter"
 )
 
+// Availability When a PetSitter can be booked and what they offer.
+type Availability struct {
+	// Activities The activities the sitter offers.
+	Activities []JobActivities `json:"activities"`
+
+	// Blackouts Times the sitter is away regardless of the weekly slots.
+	Blackouts *[]AvailabilityBlackout `json:"blackouts,omitempty"`
+
+	// DogSizes The dog sizes the sitter prefers. Empty means no preference.
+	DogSizes *[]JobDogSize `json:"dog_sizes,omitempty"`
+
+	// Timezone The IANA time zone the weekly slots are in.
+	Timezone  string     `json:"timezone"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	UserId    *string    `json:"user_id,omitempty"`
+
+	// WeeklySlots The times the sitter is free every week. Slots may overlap, and a slot ending at 24:00 joins the next day's slot starting at 00:00.
+	WeeklySlots []AvailabilitySlot `json:"weekly_slots"`
+}
+
+// AvailabilityBlackout defines model for AvailabilityBlackout.
+type AvailabilityBlackout struct {
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   *string   `json:"reason,omitempty"`
+	StartsAt time.Time `json:"starts_at"`
+}
+
+// AvailabilitySlot defines model for AvailabilitySlot.
+type AvailabilitySlot struct {
+	Day AvailabilitySlotDay `json:"day"`
+
+	// End Local time of day, HH:MM, after start. 24:00 is the end of the day.
+	End string `json:"end"`
+
+	// Start Local time of day, HH:MM.
+	Start string `json:"start"`
+}
+
+// AvailabilitySlotDay defines model for AvailabilitySlot.Day.
+type AvailabilitySlotDay string
+
+// CalendarFeed defines model for CalendarFeed.
+type CalendarFeed struct {
+	// Url The URL to subscribe to. Anyone holding it can read the feed.
+	Url *string `json:"url,omitempty"`
+}
+
 // Job defines model for Job.
 type Job struct {
 	Activities   []JobActivities   `json:"activities"`
@@ -52,12 +163,27 @@ type Job struct {
 	// CreatorUserId The user who posted this job.
 	CreatorUserId *string `json:"creator_user_id,omitempty"`
 	Description   string  `json:"description"`
-	Dog           *JobDog `json:"dog,omitempty"`
+
+	// Detached True when an occurrence of a job series was edited on its own. Edits to the rest of the series leave it alone.
+	Detached *bool   `json:"detached,omitempty"`
+	Dog      *JobDog `json:"dog,omitempty"`
+
+	// Dogs A snapshot of every dog of the job, with its age at the start of the job when it comes from a pet.
+	Dogs *[]JobDog `json:"dogs,omitempty"`
 
 	// EndsAt The date and time when this job ends.
 	EndsAt time.Time `json:"ends_at"`
 	Id     *string   `json:"id,omitempty"`
 
+	// PetIds The pets of the job's creator the job is for. The job keeps a snapshot of each in dogs, taken when the job is saved, and cannot also set dog.
+	PetIds *[]string `json:"pet_ids,omitempty"`
+
+	// RecurrenceId For an occurrence of a job series, the start its series gave it. It stays the same when the occurrence is moved.
+	RecurrenceId *time.Time `json:"recurrence_id,omitempty"`
+
+	// SeriesId The job series this job is an occurrence of.
+	SeriesId *string `json:"series_id,omitempty"`
+
 	// StartsAt The date and time when this job starts.
 	StartsAt  time.Time  `json:"starts_at"`
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
@@ -96,12 +222,123 @@ type JobDog struct {
 // JobDogSize defines model for JobDog.Size.
 type JobDogSize string
 
+// JobSeries A job that repeats, such as a walk every weekday.
+type JobSeries struct {
+	Activities []JobActivities `json:"activities"`
+
+	// ApplicantUserIds The sitters who applied to the whole series. They are applied to every occurrence created later on as well.
+	ApplicantUserIds *[]string  `json:"applicant_user_ids,omitempty"`
+	CreatedAt        *time.Time `json:"created_at,omitempty"`
+
+	// CreatorUserId The user who posted this series.
+	CreatorUserId *string `json:"creator_user_id,omitempty"`
+	Description   string  `json:"description"`
+	Dog           *JobDog `json:"dog,omitempty"`
+
+	// EndsAt The end of the first occurrence. Every occurrence lasts as long.
+	EndsAt time.Time `json:"ends_at"`
+
+	// Exceptions Local dates on which the series has no occurrence.
+	Exceptions *[]openapi_types.Date `json:"exceptions,omitempty"`
+	Id         *string               `json:"id,omitempty"`
+
+	// MaterializedUntil Every occurrence starting before this time has been created as a Job.
+	MaterializedUntil *time.Time `json:"materialized_until,omitempty"`
+
+	// Rrule An RFC 5545 recurrence rule without the RRULE prefix or a DTSTART, such as FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR. FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY.
+	Rrule string `json:"rrule"`
+
+	// StartsAt The start of the first occurrence.
+	StartsAt time.Time `json:"starts_at"`
+
+	// Timezone The IANA time zone the rule is evaluated in. Occurrences keep the local time of starts_at across daylight saving changes.
+	Timezone  string     `json:"timezone"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Pet A dog belonging to a PetOwner.
+type Pet struct {
+	// BirthDate The pet's date of birth. An estimate will do.
+	BirthDate           openapi_types.Date `json:"birth_date"`
+	Breed               string             `json:"breed"`
+	CreatedAt           *time.Time         `json:"created_at,omitempty"`
+	FeedingInstructions *string            `json:"feeding_instructions,omitempty"`
+	Id                  *string            `json:"id,omitempty"`
+
+	// MedicalNotes Conditions, medication and allergies a sitter should know about.
+	MedicalNotes *string           `json:"medical_notes,omitempty"`
+	Name         string            `json:"name"`
+	OwnerUserId  *string           `json:"owner_user_id,omitempty"`
+	Size         JobDogSize        `json:"size"`
+	Temperament  *[]PetTemperament `json:"temperament,omitempty"`
+	UpdatedAt    *time.Time        `json:"updated_at,omitempty"`
+
+	// YearsOld The pet's age today, in whole years.
+	YearsOld *int `json:"years_old,omitempty"`
+}
+
+// PetTemperament defines model for Pet.Temperament.
+type PetTemperament string
+
+// Problem An RFC 7807 problem details object.
+type Problem struct {
+	// Code A stable, machine-readable error code.
+	Code ProblemCode `json:"code"`
+
+	// Detail An explanation specific to this occurrence of the problem.
+	Detail *string `json:"detail,omitempty"`
+
+	// Errors The individual fields that failed validation.
+	Errors *[]ProblemField `json:"errors,omitempty"`
+
+	// Instance The path of the request that caused the problem.
+	Instance *string `json:"instance,omitempty"`
+
+	// RequestId The X-Request-ID of the request, for correlating with server logs.
+	RequestId *string `json:"request_id,omitempty"`
+
+	// Status The HTTP status code.
+	Status int `json:"status"`
+
+	// Title A short summary of the problem type.
+	Title string `json:"title"`
+
+	// Type A URI reference identifying the problem type.
+	Type string `json:"type"`
+}
+
+// ProblemCode A stable, machine-readable error code.
+type ProblemCode string
+
+// ProblemField defines model for ProblemField.
+type ProblemField struct {
+	In *ProblemFieldIn `json:"in,omitempty"`
+
+	// Name The parameter name, or the dotted path of the field in the request body.
+	Name   *string `json:"name,omitempty"`
+	Reason string  `json:"reason"`
+}
+
+// ProblemFieldIn defines model for ProblemField.In.
+type ProblemFieldIn string
+
 // Session defines model for Session.
 type Session struct {
 	AuthHeader *string `json:"auth_header,omitempty"`
 	UserId     *string `json:"user_id,omitempty"`
 }
 
+// SitterMatch A sitter who is free for a job.
+type SitterMatch struct {
+	// Activities The job's activities the sitter offers.
+	Activities *[]JobActivities `json:"activities,omitempty"`
+	FullName   *string          `json:"full_name,omitempty"`
+
+	// PrefersDogSize True when the sitter listed the size of every dog of the job among the sizes they prefer.
+	PrefersDogSize *bool   `json:"prefers_dog_size,omitempty"`
+	UserId         *string `json:"user_id,omitempty"`
+}
+
 // User defines model for User.
 type User struct {
 	CreatedAt *time.Time          `json:"created_at,omitempty"`
@@ -126,6 +363,16 @@ type InlineResponse200 struct {
 	TotalItems *int `json:"total_items,omitempty"`
 }
 
+// InlineResponse2001 defines model for inline_response_200_1.
+type InlineResponse2001 struct {
+	// HasMore Indicates that more items are available and can be retrieved with different offset and limit parameters.
+	HasMore *bool             `json:"has_more,omitempty"`
+	Items   *[]JobApplication `json:"items,omitempty"`
+
+	// TotalItems The total number of items, regardless of how many this response includes.
+	TotalItems *int `json:"total_items,omitempty"`
+}
+
 // GetJobsParams defines parameters for GetJobs.
 type GetJobsParams struct {
 	// Limit Limits the number of results the endpoint returns.
@@ -133,8 +380,92 @@ type GetJobsParams struct {
 
 	// Offset Skips these many items from the response.
 	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Activity Only return jobs that include all of these activities.
+	Activity *[]JobActivities `form:"activity,omitempty" json:"activity,omitempty"`
+
+	// DogSize Only return jobs with at least one dog of this size.
+	DogSize *JobDogSize `form:"dog_size,omitempty" json:"dog_size,omitempty"`
+
+	// StartsAfter Only return jobs starting at or after this time.
+	StartsAfter *time.Time `form:"starts_after,omitempty" json:"starts_after,omitempty"`
+
+	// EndsBefore Only return jobs ending at or before this time.
+	EndsBefore *time.Time `form:"ends_before,omitempty" json:"ends_before,omitempty"`
+
+	// Open When true, only return jobs without a worker. When false, only return jobs that have been filled.
+	Open *bool `form:"open,omitempty" json:"open,omitempty"`
+
+	// FitsSchedule When true, only return jobs that neither overlap nor come within the scheduling buffer of the jobs the caller is working. starts_after then defaults to now.
+	FitsSchedule *bool `form:"fits_schedule,omitempty" json:"fits_schedule,omitempty"`
+
+	// SeriesId Only return the occurrences of this job series.
+	SeriesId *string `form:"series_id,omitempty" json:"series_id,omitempty"`
+
+	// CreatorUserId Only return jobs posted by this user.
+	CreatorUserId *string `form:"creator_user_id,omitempty" json:"creator_user_id,omitempty"`
+
+	// Sort The field to sort the results by.
+	Sort *GetJobsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order The sort direction.
+	Order *GetJobsParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+}
+
+// GetJobsParamsSort defines parameters for GetJobs.
+type GetJobsParamsSort string
+
+// GetJobsParamsOrder defines parameters for GetJobs.
+type GetJobsParamsOrder string
+
+// GetJobMatchesParams defines parameters for GetJobMatches.
+type GetJobMatchesParams struct {
+	// Limit Limits the number of results the endpoint returns. Values above the server's maximum page size (50 by default) are capped.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PutJobSeriesIdParams defines parameters for PutJobSeriesId.
+type PutJobSeriesIdParams struct {
+	// From The original start of the first occurrence to edit. Defaults to now.
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+}
+
+// GetCalendarFeedParams defines parameters for GetCalendarFeed.
+type GetCalendarFeedParams struct {
+	// Token The token from the feed URL.
+	Token string `form:"token" json:"token"`
+}
+
+// GetJobApplicationsForUserParams defines parameters for GetJobApplicationsForUser.
+type GetJobApplicationsForUserParams struct {
+	// Limit Limits the number of results the endpoint returns. Values above the server's maximum page size (50 by default) are capped.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Skips these many items from the response.
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Status Only return applications in this status.
+	Status *JobApplicationStatus `form:"status,omitempty" json:"status,omitempty"`
 }
 
+// GetJobsForUserParams defines parameters for GetJobsForUser.
+type GetJobsForUserParams struct {
+	// Limit Limits the number of results the endpoint returns. Values above the server's maximum page size (50 by default) are capped.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Skips these many items from the response.
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Status Only return jobs that are still open or that have been filled.
+	Status *GetJobsForUserParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Role Only return the jobs the user is associated with through this role.
+	Role *UserRoles `form:"role,omitempty" json:"role,omitempty"`
+}
+
+// GetJobsForUserParamsStatus defines parameters for GetJobsForUser.
+type GetJobsForUserParamsStatus string
+
 // StartSessionJSONBody defines parameters for StartSession.
 type StartSessionJSONBody struct {
 	Email    *string `json:"email,omitempty"`
@@ -144,6 +475,12 @@ type StartSessionJSONBody struct {
 // UpdateJobApplicationJSONRequestBody defines body for UpdateJobApplication for application/json ContentType.
 type UpdateJobApplicationJSONRequestBody = JobApplication
 
+// PostJobSeriesJSONRequestBody defines body for PostJobSeries for application/json ContentType.
+type PostJobSeriesJSONRequestBody = JobSeries
+
+// PutJobSeriesIdJSONRequestBody defines body for PutJobSeriesId for application/json ContentType.
+type PutJobSeriesIdJSONRequestBody = JobSeries
+
 // PostJobsJSONRequestBody defines body for PostJobs for application/json ContentType.
 type PostJobsJSONRequestBody = Job
 
@@ -161,3 +498,12 @@ type PostUsersJSONRequestBody = User
 
 // PutUsersIdJSONRequestBody defines body for PutUsersId for application/json ContentType.
 type PutUsersIdJSONRequestBody = User
+
+// PutUserAvailabilityJSONRequestBody defines body for PutUserAvailability for application/json ContentType.
+type PutUserAvailabilityJSONRequestBody = Availability
+
+// PostUserPetJSONRequestBody defines body for PostUserPet for application/json ContentType.
+type PostUserPetJSONRequestBody = Pet
+
+// PutUserPetJSONRequestBody defines body for PutUserPet for application/json ContentType.
+type PutUserPetJSONRequestBody = Pet
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Repositories report missing records with ErrNotFound and uniqueness
+// violations with ErrConflict, wrapped with context about the record.
+
+type UserRepository interface {
+	PostUsers(ctx context.Context, user models.User, passwordHash string) (models.User, error)
+	GetUsersId(ctx context.Context, id string) (models.User, error)
+	// PutUsersId replaces the editable fields of the user. An empty
+	// passwordHash leaves the stored password unchanged.
+	PutUsersId(ctx context.Context, id string, user models.User, passwordHash string) (models.User, error)
+	DeleteUsersId(ctx context.Context, id string) error
+	// GetUserCredentials returns the user registered with email together
+	// with their stored password hash.
+	GetUserCredentials(ctx context.Context, email string) (models.User, string, error)
+	UpdatePasswordHash(ctx context.Context, id string, hash string) error
+}
+
+type JobRepository interface {
+	// PostJobs stores a new job. A job with a SeriesId is an occurrence of
+	// that series, and a second occurrence with the same RecurrenceId is an
+	// ErrConflict.
+	PostJobs(ctx context.Context, job models.Job) (models.Job, error)
+	GetJobsId(ctx context.Context, id string) (models.Job, error)
+	// PutJobsId replaces the editable fields of the job. Read-only fields
+	// such as CreatorUserId and WorkerUserId are left untouched. An
+	// occurrence of a series is detached from it. Moving a job that has a
+	// worker is a *ScheduleConflictError when the worker already works a
+	// job that overlaps the new times or comes within buffer of them; the
+	// check holds the worker's schedule like AcceptJobApplication does.
+	PutJobsId(ctx context.Context, id string, job models.Job, buffer time.Duration) (models.Job, error)
+	// UpdateOccurrence replaces the editable fields of an occurrence of a
+	// series on behalf of the series. It is an ErrConflict when the
+	// occurrence has been detached, and checks the worker's schedule like
+	// PutJobsId.
+	UpdateOccurrence(ctx context.Context, id string, job models.Job, buffer time.Duration) (models.Job, error)
+	// DeleteJobsId deletes a job that has no worker. It is an ErrConflict
+	// when a sitter has been accepted for the job.
+	DeleteJobsId(ctx context.Context, id string) error
+	// GetJobs returns the page of jobs selected by query together with the
+	// number of jobs matching it across all pages.
+	GetJobs(ctx context.Context, query JobQuery) ([]models.Job, int, error)
+}
+
+type JobApplicationRepository interface {
+	// CreateJobApplication stores a new APPLYING application. It is an
+	// ErrNotFound when the job is missing, and a second application by the
+	// same user to the same job is an ErrConflict.
+	CreateJobApplication(ctx context.Context, application models.JobApplication) (models.JobApplication, error)
+	GetJobApplication(ctx context.Context, id string) (models.JobApplication, error)
+	GetApplicationsByJobId(ctx context.Context, jobId string) ([]models.JobApplication, error)
+	// GetJobApplications returns a page of the applications matching query
+	// together with the total number of matches.
+	GetJobApplications(ctx context.Context, query JobApplicationQuery) ([]models.JobApplication, int, error)
+	// AcceptJobApplication atomically accepts a pending application, makes
+	// its applicant the job's worker and denies the job's other pending
+	// applications. It is an ErrConflict when the application is not pending
+	// or the job already has a worker, and a *ScheduleConflictError when the
+	// applicant already works a job that overlaps this one or comes within
+	// buffer of it.
+	AcceptJobApplication(ctx context.Context, id string, buffer time.Duration) (models.JobApplication, error)
+	// DenyJobApplication denies a pending application.
+	DenyJobApplication(ctx context.Context, id string) (models.JobApplication, error)
+	// WithdrawJobApplication deletes the application, reopening the job when
+	// the application had been accepted.
+	WithdrawJobApplication(ctx context.Context, id string) error
+	// DeleteJob atomically deletes a job that has no worker together with
+	// every application made to it. It is an ErrConflict when a sitter has
+	// been accepted for the job. before, when not nil, runs as part of the
+	// delete, which fails with its error.
+	DeleteJob(ctx context.Context, jobId string, before func(ctx context.Context) error) error
+}
+
+type JobSeriesRepository interface {
+	PostJobSeries(ctx context.Context, series models.JobSeries) (models.JobSeries, error)
+	GetJobSeries(ctx context.Context, id string) (models.JobSeries, error)
+	// PutJobSeries replaces the editable fields of the series, including
+	// its exceptions.
+	PutJobSeries(ctx context.Context, id string, series models.JobSeries) (models.JobSeries, error)
+	// AddJobSeriesException adds date to the exceptions of the series.
+	AddJobSeriesException(ctx context.Context, id string, date openapi_types.Date) error
+	// AddJobSeriesApplicant records that userId applied to the whole series.
+	AddJobSeriesApplicant(ctx context.Context, id string, userId string) error
+	// SetJobSeriesMaterializedUntil records that the occurrences starting
+	// before until have been created. It never moves the time back.
+	SetJobSeriesMaterializedUntil(ctx context.Context, id string, until time.Time) error
+	// GetJobSeriesDue returns the series whose occurrences have been
+	// created up to some time before before.
+	GetJobSeriesDue(ctx context.Context, before time.Time) ([]models.JobSeries, error)
+}
+
+// AvailabilityRepository stores the availability of sitters, one per user.
+type AvailabilityRepository interface {
+	GetAvailability(ctx context.Context, userId string) (models.Availability, error)
+	// PutAvailability creates or replaces the availability of the user.
+	PutAvailability(ctx context.Context, userId string, availability models.Availability) (models.Availability, error)
+	DeleteAvailability(ctx context.Context, userId string) error
+	// GetAvailabilities returns the availability of every sitter offering at
+	// least one of activities.
+	GetAvailabilities(ctx context.Context, activities []models.JobActivities) ([]models.Availability, error)
+}
+
+// PetRepository stores the pets of PetOwners.
+type PetRepository interface {
+	PostPet(ctx context.Context, pet models.Pet) (models.Pet, error)
+	GetPet(ctx context.Context, id string) (models.Pet, error)
+	// GetPets returns the pets of the owner ordered by name.
+	GetPets(ctx context.Context, ownerUserId string) ([]models.Pet, error)
+	// PutPet replaces the editable fields of the pet.
+	PutPet(ctx context.Context, id string, pet models.Pet) (models.Pet, error)
+	DeletePet(ctx context.Context, id string) error
+	// DeletePetsByOwner removes every pet of the owner.
+	DeletePetsByOwner(ctx context.Context, ownerUserId string) error
+}
+
+// CalendarFeedRepository stores the calendar feed of each user, one per user.
+type CalendarFeedRepository interface {
+	// GetCalendarFeedByToken returns the feed opened by the token with the
+	// given hash.
+	GetCalendarFeedByToken(ctx context.Context, tokenHash string) (CalendarFeed, error)
+	// PutCalendarToken creates the user's feed, or replaces the token of an
+	// existing one, keeping its events. A token hash already used by another
+	// feed is an ErrConflict.
+	PutCalendarToken(ctx context.Context, userId string, tokenHash string) error
+	// PutCalendarEvents replaces the events published by the user's feed
+	// and bumps its Version, provided the feed is still at version. It is
+	// an ErrConflict when another call replaced them first.
+	PutCalendarEvents(ctx context.Context, userId string, version int, events []CalendarEvent) error
+	DeleteCalendarFeed(ctx context.Context, userId string) error
+}
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session Session) error
+	GetSession(ctx context.Context, id string) (Session, error)
+	// DeleteUserSessions removes every session of the user.
+	DeleteUserSessions(ctx context.Context, userId string) error
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Interval is the span of time from Start up to, but not including, End.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Overlaps reports whether i and o share any instant.
+func (i Interval) Overlaps(o Interval) bool {
+	return i.Start.Before(o.End) && o.Start.Before(i.End)
+}
+
+// Pad widens i by buffer on either side, so that anything overlapping the
+// result is closer than buffer to i.
+func (i Interval) Pad(buffer time.Duration) Interval {
+	return Interval{Start: i.Start.Add(-buffer), End: i.End.Add(buffer)}
+}
+
+// ScheduleConflictError is returned when a sitter would be accepted for, or a
+// job they work would be moved to, a time that overlaps, or comes within the
+// scheduling buffer of, a job they are already working. It matches
+// ErrConflict.
+type ScheduleConflictError struct {
+	// JobId is the sitter's job that is in the way.
+	JobId string
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return fmt.Sprintf("the sitter is already working job %s at that time", e.JobId)
+}
+
+func (e *ScheduleConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
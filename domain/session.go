@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Session is the server side record of a login. Tokens handed to clients
+// refer to it so that a session can be revoked before its token expires.
+type Session struct {
+	Id        string
+	UserId    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+type availabilityRecord struct {
+	userId      string
+	timezone    string
+	weeklySlots []models.AvailabilitySlot
+	blackouts   []models.AvailabilityBlackout
+	activities  []models.JobActivities
+	dogSizes    []models.JobDogSize
+	updatedAt   time.Time
+}
+
+func (r availabilityRecord) toModel() models.Availability {
+	blackouts := make([]models.AvailabilityBlackout, 0, len(r.blackouts))
+	for _, blackout := range r.blackouts {
+		if blackout.Reason != nil {
+			reason := *blackout.Reason
+			blackout.Reason = &reason
+		}
+		blackouts = append(blackouts, blackout)
+	}
+	dogSizes := append([]models.JobDogSize{}, r.dogSizes...)
+
+	return models.Availability{
+		UserId:      &r.userId,
+		Timezone:    r.timezone,
+		WeeklySlots: append([]models.AvailabilitySlot{}, r.weeklySlots...),
+		Blackouts:   &blackouts,
+		Activities:  append([]models.JobActivities{}, r.activities...),
+		DogSizes:    &dogSizes,
+		UpdatedAt:   &r.updatedAt,
+	}
+}
+
+var _ domain.AvailabilityRepository = (*AvailabilityRepository)(nil)
+
+type AvailabilityRepository struct {
+	mu           sync.RWMutex
+	availability map[string]availabilityRecord
+}
+
+func NewAvailabilityRepository() *AvailabilityRepository {
+	return &AvailabilityRepository{
+		availability: make(map[string]availabilityRecord),
+	}
+}
+
+func (a *AvailabilityRepository) GetAvailability(ctx context.Context, userId string) (models.Availability, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	rec, ok := a.availability[userId]
+	if !ok {
+		return models.Availability{}, fmt.Errorf("availability of user %s: %w", userId, domain.ErrNotFound)
+	}
+
+	return rec.toModel(), nil
+}
+
+func (a *AvailabilityRepository) PutAvailability(ctx context.Context, userId string, availability models.Availability) (models.Availability, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rec := availabilityRecord{
+		userId:      userId,
+		timezone:    availability.Timezone,
+		weeklySlots: append([]models.AvailabilitySlot(nil), availability.WeeklySlots...),
+		activities:  append([]models.JobActivities(nil), availability.Activities...),
+		updatedAt:   now(),
+	}
+	if availability.Blackouts != nil {
+		for _, blackout := range *availability.Blackouts {
+			blackout.StartsAt = blackout.StartsAt.UTC().Truncate(time.Millisecond)
+			blackout.EndsAt = blackout.EndsAt.UTC().Truncate(time.Millisecond)
+			if blackout.Reason != nil {
+				reason := *blackout.Reason
+				blackout.Reason = &reason
+			}
+			rec.blackouts = append(rec.blackouts, blackout)
+		}
+	}
+	if availability.DogSizes != nil {
+		rec.dogSizes = append(rec.dogSizes, *availability.DogSizes...)
+	}
+	a.availability[userId] = rec
+
+	return rec.toModel(), nil
+}
+
+func (a *AvailabilityRepository) DeleteAvailability(ctx context.Context, userId string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.availability[userId]; !ok {
+		return fmt.Errorf("availability of user %s: %w", userId, domain.ErrNotFound)
+	}
+	delete(a.availability, userId)
+
+	return nil
+}
+
+func (a *AvailabilityRepository) GetAvailabilities(ctx context.Context, activities []models.JobActivities) ([]models.Availability, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var found []availabilityRecord
+	for _, rec := range a.availability {
+		if slices.ContainsFunc(rec.activities, func(activity models.JobActivities) bool {
+			return slices.Contains(activities, activity)
+		}) {
+			found = append(found, rec)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].userId < found[j].userId
+	})
+
+	availabilities := make([]models.Availability, 0, len(found))
+	for _, rec := range found {
+		availabilities = append(availabilities, rec.toModel())
+	}
+
+	return availabilities, nil
+}
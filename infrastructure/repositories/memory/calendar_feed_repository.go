@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bersennaidoo/agentco/domain"
+)
+
+var _ domain.CalendarFeedRepository = (*CalendarFeedRepository)(nil)
+
+type CalendarFeedRepository struct {
+	mu    sync.RWMutex
+	feeds map[string]domain.CalendarFeed
+}
+
+func NewCalendarFeedRepository() *CalendarFeedRepository {
+	return &CalendarFeedRepository{
+		feeds: make(map[string]domain.CalendarFeed),
+	}
+}
+
+func (c *CalendarFeedRepository) GetCalendarFeedByToken(ctx context.Context, tokenHash string) (domain.CalendarFeed, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, feed := range c.feeds {
+		if feed.TokenHash == tokenHash {
+			feed.Events = append([]domain.CalendarEvent(nil), feed.Events...)
+			return feed, nil
+		}
+	}
+
+	return domain.CalendarFeed{}, fmt.Errorf("calendar feed: %w", domain.ErrNotFound)
+}
+
+func (c *CalendarFeedRepository) PutCalendarToken(ctx context.Context, userId string, tokenHash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, other := range c.feeds {
+		if other.TokenHash == tokenHash && other.UserId != userId {
+			return fmt.Errorf("calendar feed token: %w", domain.ErrConflict)
+		}
+	}
+
+	feed := c.feeds[userId]
+	feed.UserId = userId
+	feed.TokenHash = tokenHash
+	c.feeds[userId] = feed
+
+	return nil
+}
+
+func (c *CalendarFeedRepository) PutCalendarEvents(ctx context.Context, userId string, version int, events []domain.CalendarEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	feed, ok := c.feeds[userId]
+	if !ok {
+		return fmt.Errorf("calendar feed of user %s: %w", userId, domain.ErrNotFound)
+	}
+	if feed.Version != version {
+		return fmt.Errorf("calendar feed of user %s is at version %d, not %d: %w", userId, feed.Version, version, domain.ErrConflict)
+	}
+	feed.Events = append([]domain.CalendarEvent(nil), events...)
+	feed.Version++
+	c.feeds[userId] = feed
+
+	return nil
+}
+
+func (c *CalendarFeedRepository) DeleteCalendarFeed(ctx context.Context, userId string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.feeds[userId]; !ok {
+		return fmt.Errorf("calendar feed of user %s: %w", userId, domain.ErrNotFound)
+	}
+	delete(c.feeds, userId)
+
+	return nil
+}
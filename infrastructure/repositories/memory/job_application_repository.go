@@ -0,0 +1,275 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+type jobApplicationRecord struct {
+	id        string
+	jobId     string
+	userId    string
+	status    models.JobApplicationStatus
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+func (r jobApplicationRecord) toModel() models.JobApplication {
+	return models.JobApplication{
+		Id:     &r.id,
+		JobId:  &r.jobId,
+		UserId: &r.userId,
+		Status: &r.status,
+	}
+}
+
+var _ domain.JobApplicationRepository = (*JobApplicationRepository)(nil)
+
+// JobApplicationRepository shares jobs with a JobRepository so that accepting
+// and withdrawing can update the job in the same critical section.
+type JobApplicationRepository struct {
+	mu           sync.RWMutex
+	applications map[string]jobApplicationRecord
+	jobs         *JobRepository
+}
+
+func NewJobApplicationRepository(jobs *JobRepository) *JobApplicationRepository {
+	return &JobApplicationRepository{
+		applications: make(map[string]jobApplicationRecord),
+		jobs:         jobs,
+	}
+}
+
+func (j *JobApplicationRepository) CreateJobApplication(ctx context.Context, application models.JobApplication) (models.JobApplication, error) {
+	j.jobs.mu.RLock()
+	defer j.jobs.mu.RUnlock()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	created := now()
+	rec := jobApplicationRecord{
+		id:        newID(),
+		status:    models.APPLYING,
+		createdAt: created,
+		updatedAt: created,
+	}
+	if application.JobId != nil {
+		rec.jobId = *application.JobId
+	}
+	if application.UserId != nil {
+		rec.userId = *application.UserId
+	}
+
+	if _, ok := j.jobs.jobs[rec.jobId]; !ok {
+		return models.JobApplication{}, fmt.Errorf("job %s: %w", rec.jobId, domain.ErrNotFound)
+	}
+	for _, other := range j.applications {
+		if other.jobId == rec.jobId && other.userId == rec.userId {
+			return models.JobApplication{}, fmt.Errorf("application to job %s: %w", rec.jobId, domain.ErrConflict)
+		}
+	}
+	j.applications[rec.id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (j *JobApplicationRepository) GetJobApplication(ctx context.Context, id string) (models.JobApplication, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	rec, ok := j.applications[id]
+	if !ok {
+		return models.JobApplication{}, fmt.Errorf("job application %s: %w", id, domain.ErrNotFound)
+	}
+
+	return rec.toModel(), nil
+}
+
+func (j *JobApplicationRepository) GetApplicationsByJobId(ctx context.Context, jobId string) ([]models.JobApplication, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var recs []jobApplicationRecord
+	for _, rec := range j.applications {
+		if rec.jobId == jobId {
+			recs = append(recs, rec)
+		}
+	}
+	sortApplications(recs)
+
+	applications := make([]models.JobApplication, 0, len(recs))
+	for _, rec := range recs {
+		applications = append(applications, rec.toModel())
+	}
+
+	return applications, nil
+}
+
+func (j *JobApplicationRepository) GetJobApplications(ctx context.Context, query domain.JobApplicationQuery) ([]models.JobApplication, int, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var recs []jobApplicationRecord
+	for _, rec := range j.applications {
+		if rec.userId != query.UserId {
+			continue
+		}
+		if query.Status != nil && rec.status != *query.Status {
+			continue
+		}
+		recs = append(recs, rec)
+	}
+	sortApplications(recs)
+	slices.Reverse(recs)
+
+	total := len(recs)
+	recs = recs[min(query.Offset, total):]
+	if query.Limit > 0 && len(recs) > query.Limit {
+		recs = recs[:query.Limit]
+	}
+
+	applications := make([]models.JobApplication, 0, len(recs))
+	for _, rec := range recs {
+		applications = append(applications, rec.toModel())
+	}
+
+	return applications, total, nil
+}
+
+func (j *JobApplicationRepository) AcceptJobApplication(ctx context.Context, id string, buffer time.Duration) (models.JobApplication, error) {
+	j.jobs.mu.Lock()
+	defer j.jobs.mu.Unlock()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec, err := j.pending(id)
+	if err != nil {
+		return models.JobApplication{}, err
+	}
+
+	job, ok := j.jobs.jobs[rec.jobId]
+	if !ok || job.workerUserId != nil {
+		return models.JobApplication{}, fmt.Errorf("job %s is missing or already filled: %w", rec.jobId, domain.ErrConflict)
+	}
+
+	if err := j.jobs.checkSchedule(rec.userId, job.id, job.interval().Pad(buffer)); err != nil {
+		return models.JobApplication{}, err
+	}
+
+	updated := now()
+	worker := rec.userId
+	job.workerUserId = &worker
+	job.updatedAt = updated
+	j.jobs.jobs[job.id] = job
+
+	for otherId, other := range j.applications {
+		if other.jobId == rec.jobId && other.status == models.APPLYING {
+			other.status = models.DENIED
+			other.updatedAt = updated
+			j.applications[otherId] = other
+		}
+	}
+
+	rec.status = models.ACCEPTED
+	rec.updatedAt = updated
+	j.applications[id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (j *JobApplicationRepository) DenyJobApplication(ctx context.Context, id string) (models.JobApplication, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec, err := j.pending(id)
+	if err != nil {
+		return models.JobApplication{}, err
+	}
+
+	rec.status = models.DENIED
+	rec.updatedAt = now()
+	j.applications[id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (j *JobApplicationRepository) WithdrawJobApplication(ctx context.Context, id string) error {
+	j.jobs.mu.Lock()
+	defer j.jobs.mu.Unlock()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec, ok := j.applications[id]
+	if !ok {
+		return fmt.Errorf("job application %s: %w", id, domain.ErrNotFound)
+	}
+	delete(j.applications, id)
+
+	if rec.status != models.ACCEPTED {
+		return nil
+	}
+
+	job, ok := j.jobs.jobs[rec.jobId]
+	if ok && job.workerUserId != nil && *job.workerUserId == rec.userId {
+		job.workerUserId = nil
+		job.updatedAt = now()
+		j.jobs.jobs[job.id] = job
+	}
+
+	return nil
+}
+
+func (j *JobApplicationRepository) DeleteJob(ctx context.Context, jobId string, before func(ctx context.Context) error) error {
+	j.jobs.mu.Lock()
+	defer j.jobs.mu.Unlock()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if rec, ok := j.jobs.jobs[jobId]; ok && rec.workerUserId == nil && before != nil {
+		if err := before(ctx); err != nil {
+			return err
+		}
+	}
+	if err := j.jobs.deleteUnfilled(jobId); err != nil {
+		return err
+	}
+	for id, rec := range j.applications {
+		if rec.jobId == jobId {
+			delete(j.applications, id)
+		}
+	}
+
+	return nil
+}
+
+// pending returns the application when it is APPLYING. It must be called
+// with j.mu held.
+func (j *JobApplicationRepository) pending(id string) (jobApplicationRecord, error) {
+	rec, ok := j.applications[id]
+	if !ok {
+		return rec, fmt.Errorf("job application %s: %w", id, domain.ErrNotFound)
+	}
+	if rec.status != models.APPLYING {
+		return rec, fmt.Errorf("job application %s is %s, not %s: %w", id, rec.status, models.APPLYING, domain.ErrConflict)
+	}
+
+	return rec, nil
+}
+
+// sortApplications orders applications the way the Mongo repository does,
+// oldest first.
+func sortApplications(recs []jobApplicationRecord) {
+	sort.Slice(recs, func(a, b int) bool {
+		if !recs[a].createdAt.Equal(recs[b].createdAt) {
+			return recs[a].createdAt.Before(recs[b].createdAt)
+		}
+		return recs[a].id < recs[b].id
+	})
+}
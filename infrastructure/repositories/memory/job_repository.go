@@ -0,0 +1,364 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+)
+
+type jobRecord struct {
+	id            string
+	creatorUserId string
+	workerUserId  *string
+	description   string
+	dog           *models.JobDog
+	dogs          []models.JobDog
+	petIds        []string
+	activities    []models.JobActivities
+	startsAt      time.Time
+	endsAt        time.Time
+	seriesId      *string
+	recurrenceId  time.Time
+	detached      bool
+	createdAt     time.Time
+	updatedAt     time.Time
+}
+
+func (r jobRecord) toModel() models.Job {
+	job := models.Job{
+		Id:            &r.id,
+		CreatorUserId: &r.creatorUserId,
+		Description:   r.description,
+		Dog:           copyDog(r.dog),
+		Activities:    append([]models.JobActivities(nil), r.activities...),
+		StartsAt:      r.startsAt,
+		EndsAt:        r.endsAt,
+		CreatedAt:     &r.createdAt,
+		UpdatedAt:     &r.updatedAt,
+	}
+	if r.dogs != nil {
+		dogs := copyDogs(r.dogs)
+		job.Dogs = &dogs
+	}
+	if r.petIds != nil {
+		petIds := append([]string{}, r.petIds...)
+		job.PetIds = &petIds
+	}
+	if r.workerUserId != nil {
+		worker := *r.workerUserId
+		job.WorkerUserId = &worker
+	}
+	if r.seriesId != nil {
+		seriesId, recurrenceId, detached := *r.seriesId, r.recurrenceId, r.detached
+		job.SeriesId = &seriesId
+		job.RecurrenceId = &recurrenceId
+		job.Detached = &detached
+	}
+
+	return job
+}
+
+func copyDog(dog *models.JobDog) *models.JobDog {
+	if dog == nil {
+		return nil
+	}
+
+	c := *dog
+	if dog.Name != nil {
+		name := *dog.Name
+		c.Name = &name
+	}
+
+	return &c
+}
+
+func copyDogs(dogs []models.JobDog) []models.JobDog {
+	c := make([]models.JobDog, 0, len(dogs))
+	for _, dog := range dogs {
+		c = append(c, *copyDog(&dog))
+	}
+
+	return c
+}
+
+var _ domain.JobRepository = (*JobRepository)(nil)
+
+type JobRepository struct {
+	mu   sync.RWMutex
+	jobs map[string]jobRecord
+}
+
+func NewJobRepository() *JobRepository {
+	return &JobRepository{
+		jobs: make(map[string]jobRecord),
+	}
+}
+
+func (j *JobRepository) PostJobs(ctx context.Context, job models.Job) (models.Job, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	created := now()
+	rec := jobRecord{
+		id:          newID(),
+		description: job.Description,
+		dog:         copyDog(job.Dog),
+		activities:  append([]models.JobActivities(nil), job.Activities...),
+		startsAt:    job.StartsAt.UTC().Truncate(time.Millisecond),
+		endsAt:      job.EndsAt.UTC().Truncate(time.Millisecond),
+		createdAt:   created,
+		updatedAt:   created,
+	}
+	rec.setPets(job)
+	if job.CreatorUserId != nil {
+		rec.creatorUserId = *job.CreatorUserId
+	}
+	if job.SeriesId != nil && job.RecurrenceId != nil {
+		seriesId := *job.SeriesId
+		rec.seriesId = &seriesId
+		rec.recurrenceId = job.RecurrenceId.UTC().Truncate(time.Millisecond)
+		for _, other := range j.jobs {
+			if other.seriesId != nil && *other.seriesId == seriesId && other.recurrenceId.Equal(rec.recurrenceId) {
+				return models.Job{}, fmt.Errorf("occurrence %s of series %s: %w", rec.recurrenceId, seriesId, domain.ErrConflict)
+			}
+		}
+	}
+	j.jobs[rec.id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (j *JobRepository) GetJobsId(ctx context.Context, id string) (models.Job, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	rec, ok := j.jobs[id]
+	if !ok {
+		return models.Job{}, fmt.Errorf("job %s: %w", id, domain.ErrNotFound)
+	}
+
+	return rec.toModel(), nil
+}
+
+func (j *JobRepository) PutJobsId(ctx context.Context, id string, job models.Job, buffer time.Duration) (models.Job, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec, ok := j.jobs[id]
+	if !ok {
+		return models.Job{}, fmt.Errorf("job %s: %w", id, domain.ErrNotFound)
+	}
+	if err := j.checkReschedule(rec, job, buffer); err != nil {
+		return models.Job{}, err
+	}
+
+	rec.update(job)
+	rec.detached = rec.seriesId != nil
+	j.jobs[id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (j *JobRepository) UpdateOccurrence(ctx context.Context, id string, job models.Job, buffer time.Duration) (models.Job, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec, ok := j.jobs[id]
+	if !ok {
+		return models.Job{}, fmt.Errorf("job %s: %w", id, domain.ErrNotFound)
+	}
+	if rec.seriesId == nil || rec.detached {
+		return models.Job{}, fmt.Errorf("job %s is not an attached occurrence: %w", id, domain.ErrConflict)
+	}
+	if err := j.checkReschedule(rec, job, buffer); err != nil {
+		return models.Job{}, err
+	}
+
+	rec.update(job)
+	j.jobs[id] = rec
+
+	return rec.toModel(), nil
+}
+
+// checkReschedule fails with a *domain.ScheduleConflictError when rec has a
+// worker and job moves it to within buffer of another job they work. The
+// caller holds j.mu.
+func (j *JobRepository) checkReschedule(rec jobRecord, job models.Job, buffer time.Duration) error {
+	interval := domain.Interval{
+		Start: job.StartsAt.UTC().Truncate(time.Millisecond),
+		End:   job.EndsAt.UTC().Truncate(time.Millisecond),
+	}
+	if rec.workerUserId == nil || interval.Start.Equal(rec.startsAt) && interval.End.Equal(rec.endsAt) {
+		return nil
+	}
+
+	return j.checkSchedule(*rec.workerUserId, rec.id, interval.Pad(buffer))
+}
+
+// checkSchedule fails with a *domain.ScheduleConflictError naming the
+// earliest job other than jobId that userId works within window. The caller
+// holds j.mu.
+func (j *JobRepository) checkSchedule(userId, jobId string, window domain.Interval) error {
+	var conflict *jobRecord
+	for _, other := range j.jobs {
+		if other.id == jobId || other.workerUserId == nil || *other.workerUserId != userId || !other.interval().Overlaps(window) {
+			continue
+		}
+		if conflict == nil || other.startsAt.Before(conflict.startsAt) {
+			other := other
+			conflict = &other
+		}
+	}
+	if conflict != nil {
+		return &domain.ScheduleConflictError{JobId: conflict.id}
+	}
+
+	return nil
+}
+
+// update replaces the editable fields of r with those of job.
+func (r *jobRecord) update(job models.Job) {
+	r.description = job.Description
+	r.dog = copyDog(job.Dog)
+	r.setPets(job)
+	r.activities = append([]models.JobActivities(nil), job.Activities...)
+	r.startsAt = job.StartsAt.UTC().Truncate(time.Millisecond)
+	r.endsAt = job.EndsAt.UTC().Truncate(time.Millisecond)
+	r.updatedAt = now()
+}
+
+// setPets copies the dogs and pet ids of job to r. A job with only an
+// inline dog gets it as its only dog.
+func (r *jobRecord) setPets(job models.Job) {
+	r.dogs, r.petIds = nil, nil
+	if dogs := domain.JobDogs(job); dogs != nil {
+		r.dogs = copyDogs(dogs)
+	}
+	if job.PetIds != nil {
+		r.petIds = append([]string{}, *job.PetIds...)
+	}
+}
+
+func (j *JobRepository) DeleteJobsId(ctx context.Context, id string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.deleteUnfilled(id)
+}
+
+// deleteUnfilled deletes the job id unless it has a worker. It must be
+// called with j.mu held.
+func (j *JobRepository) deleteUnfilled(id string) error {
+	rec, ok := j.jobs[id]
+	if !ok {
+		return fmt.Errorf("job %s: %w", id, domain.ErrNotFound)
+	}
+	if rec.workerUserId != nil {
+		return fmt.Errorf("job %s has an accepted application: %w", id, domain.ErrConflict)
+	}
+	delete(j.jobs, id)
+
+	return nil
+}
+
+func (j *JobRepository) GetJobs(ctx context.Context, query domain.JobQuery) ([]models.Job, int, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var recs []jobRecord
+	for _, rec := range j.jobs {
+		if rec.matches(query) {
+			recs = append(recs, rec)
+		}
+	}
+
+	sort.Slice(recs, func(a, b int) bool {
+		x, y := recs[a], recs[b]
+		if query.Descending {
+			x, y = y, x
+		}
+
+		xt, yt := x.startsAt, y.startsAt
+		if query.SortBy == models.CreatedAt {
+			xt, yt = x.createdAt, y.createdAt
+		}
+		if !xt.Equal(yt) {
+			return xt.Before(yt)
+		}
+		return x.id < y.id
+	})
+
+	total := len(recs)
+	recs = recs[min(query.Offset, total):]
+	if query.Limit > 0 && len(recs) > query.Limit {
+		recs = recs[:query.Limit]
+	}
+
+	jobs := make([]models.Job, 0, len(recs))
+	for _, rec := range recs {
+		jobs = append(jobs, rec.toModel())
+	}
+
+	return jobs, total, nil
+}
+
+func (r jobRecord) interval() domain.Interval {
+	return domain.Interval{Start: r.startsAt, End: r.endsAt}
+}
+
+func (r jobRecord) matches(query domain.JobQuery) bool {
+	for _, activity := range query.Activities {
+		if !slices.Contains(r.activities, activity) {
+			return false
+		}
+	}
+	if query.DogSize != nil && !slices.ContainsFunc(r.dogs, func(dog models.JobDog) bool { return dog.Size == *query.DogSize }) {
+		return false
+	}
+	if query.StartsAfter != nil && r.startsAt.Before(*query.StartsAfter) {
+		return false
+	}
+	if query.EndsBefore != nil && r.endsAt.After(*query.EndsBefore) {
+		return false
+	}
+	if query.StartsBefore != nil && !r.startsAt.Before(*query.StartsBefore) {
+		return false
+	}
+	if query.EndsAfter != nil && !r.endsAt.After(*query.EndsAfter) {
+		return false
+	}
+	if query.Open != nil && *query.Open != (r.workerUserId == nil) {
+		return false
+	}
+	if query.CreatorUserId != nil && r.creatorUserId != *query.CreatorUserId {
+		return false
+	}
+	if query.WorkerUserId != nil && (r.workerUserId == nil || *r.workerUserId != *query.WorkerUserId) {
+		return false
+	}
+	if query.WorkerUserIds != nil && (r.workerUserId == nil || !slices.Contains(query.WorkerUserIds, *r.workerUserId)) {
+		return false
+	}
+	if id := query.ParticipantUserId; id != nil && r.creatorUserId != *id && (r.workerUserId == nil || *r.workerUserId != *id) {
+		return false
+	}
+	if query.SeriesId != nil && (r.seriesId == nil || *r.seriesId != *query.SeriesId) {
+		return false
+	}
+	if query.RecurringAfter != nil && (r.seriesId == nil || r.recurrenceId.Before(*query.RecurringAfter)) {
+		return false
+	}
+	for _, busy := range query.Avoid {
+		if r.interval().Overlaps(busy) {
+			return false
+		}
+	}
+
+	return true
+}
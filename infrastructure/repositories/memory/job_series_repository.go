@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type jobSeriesRecord struct {
+	id                string
+	creatorUserId     string
+	rrule             string
+	timezone          string
+	startsAt          time.Time
+	endsAt            time.Time
+	exceptions        []openapi_types.Date
+	description       string
+	dog               *models.JobDog
+	activities        []models.JobActivities
+	applicantUserIds  []string
+	materializedUntil time.Time
+	createdAt         time.Time
+	updatedAt         time.Time
+}
+
+func (r jobSeriesRecord) toModel() models.JobSeries {
+	exceptions := append([]openapi_types.Date{}, r.exceptions...)
+	applicants := append([]string{}, r.applicantUserIds...)
+
+	series := models.JobSeries{
+		Id:               &r.id,
+		CreatorUserId:    &r.creatorUserId,
+		Rrule:            r.rrule,
+		Timezone:         r.timezone,
+		StartsAt:         r.startsAt,
+		EndsAt:           r.endsAt,
+		Exceptions:       &exceptions,
+		Description:      r.description,
+		Dog:              copyDog(r.dog),
+		Activities:       append([]models.JobActivities(nil), r.activities...),
+		ApplicantUserIds: &applicants,
+		CreatedAt:        &r.createdAt,
+		UpdatedAt:        &r.updatedAt,
+	}
+	if !r.materializedUntil.IsZero() {
+		series.MaterializedUntil = &r.materializedUntil
+	}
+
+	return series
+}
+
+// update replaces the editable fields of r with those of series.
+func (r *jobSeriesRecord) update(series models.JobSeries) {
+	r.rrule = series.Rrule
+	r.timezone = series.Timezone
+	r.startsAt = series.StartsAt.UTC().Truncate(time.Millisecond)
+	r.endsAt = series.EndsAt.UTC().Truncate(time.Millisecond)
+	r.exceptions = nil
+	if series.Exceptions != nil {
+		r.exceptions = append(r.exceptions, *series.Exceptions...)
+	}
+	r.description = series.Description
+	r.dog = copyDog(series.Dog)
+	r.activities = append([]models.JobActivities(nil), series.Activities...)
+	r.updatedAt = now()
+}
+
+var _ domain.JobSeriesRepository = (*JobSeriesRepository)(nil)
+
+type JobSeriesRepository struct {
+	mu     sync.RWMutex
+	series map[string]jobSeriesRecord
+}
+
+func NewJobSeriesRepository() *JobSeriesRepository {
+	return &JobSeriesRepository{
+		series: make(map[string]jobSeriesRecord),
+	}
+}
+
+func (j *JobSeriesRepository) PostJobSeries(ctx context.Context, series models.JobSeries) (models.JobSeries, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec := jobSeriesRecord{id: newID()}
+	if series.CreatorUserId != nil {
+		rec.creatorUserId = *series.CreatorUserId
+	}
+	rec.update(series)
+	rec.createdAt = rec.updatedAt
+	j.series[rec.id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (j *JobSeriesRepository) GetJobSeries(ctx context.Context, id string) (models.JobSeries, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	rec, ok := j.series[id]
+	if !ok {
+		return models.JobSeries{}, fmt.Errorf("job series %s: %w", id, domain.ErrNotFound)
+	}
+
+	return rec.toModel(), nil
+}
+
+func (j *JobSeriesRepository) PutJobSeries(ctx context.Context, id string, series models.JobSeries) (models.JobSeries, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec, ok := j.series[id]
+	if !ok {
+		return models.JobSeries{}, fmt.Errorf("job series %s: %w", id, domain.ErrNotFound)
+	}
+	rec.update(series)
+	j.series[id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (j *JobSeriesRepository) AddJobSeriesException(ctx context.Context, id string, date openapi_types.Date) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec, ok := j.series[id]
+	if !ok {
+		return fmt.Errorf("job series %s: %w", id, domain.ErrNotFound)
+	}
+	if !slices.ContainsFunc(rec.exceptions, func(d openapi_types.Date) bool { return d.String() == date.String() }) {
+		rec.exceptions = append(slices.Clone(rec.exceptions), date)
+		rec.updatedAt = now()
+		j.series[id] = rec
+	}
+
+	return nil
+}
+
+func (j *JobSeriesRepository) AddJobSeriesApplicant(ctx context.Context, id string, userId string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec, ok := j.series[id]
+	if !ok {
+		return fmt.Errorf("job series %s: %w", id, domain.ErrNotFound)
+	}
+	if !slices.Contains(rec.applicantUserIds, userId) {
+		rec.applicantUserIds = append(slices.Clone(rec.applicantUserIds), userId)
+		rec.updatedAt = now()
+		j.series[id] = rec
+	}
+
+	return nil
+}
+
+func (j *JobSeriesRepository) SetJobSeriesMaterializedUntil(ctx context.Context, id string, until time.Time) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	rec, ok := j.series[id]
+	if !ok {
+		return fmt.Errorf("job series %s: %w", id, domain.ErrNotFound)
+	}
+	until = until.UTC().Truncate(time.Millisecond)
+	if until.After(rec.materializedUntil) {
+		rec.materializedUntil = until
+		j.series[id] = rec
+	}
+
+	return nil
+}
+
+func (j *JobSeriesRepository) GetJobSeriesDue(ctx context.Context, before time.Time) ([]models.JobSeries, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var due []models.JobSeries
+	for _, rec := range j.series {
+		if rec.materializedUntil.Before(before) {
+			due = append(due, rec.toModel())
+		}
+	}
+
+	return due, nil
+}
@@ -0,0 +1,23 @@
+// Package memory provides thread-safe in-memory implementations of the domain
+// repositories. They behave like their Mongo counterparts and are meant for
+// tests and local development.
+package memory
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+func newID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/infrastructure/repositories/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, repotest.Factory{
+		Users: func(t *testing.T) domain.UserRepository {
+			return NewUserRepository()
+		},
+		Jobs: func(t *testing.T) domain.JobRepository {
+			return NewJobRepository()
+		},
+		JobApplications: func(t *testing.T) (domain.JobRepository, domain.JobApplicationRepository) {
+			jobs := NewJobRepository()
+			return jobs, NewJobApplicationRepository(jobs)
+		},
+		Sessions: func(t *testing.T) domain.SessionRepository {
+			return NewSessionRepository()
+		},
+		JobSeries: func(t *testing.T) domain.JobSeriesRepository {
+			return NewJobSeriesRepository()
+		},
+		Availability: func(t *testing.T) domain.AvailabilityRepository {
+			return NewAvailabilityRepository()
+		},
+		CalendarFeeds: func(t *testing.T) domain.CalendarFeedRepository {
+			return NewCalendarFeedRepository()
+		},
+		Pets: func(t *testing.T) domain.PetRepository {
+			return NewPetRepository()
+		},
+	})
+}
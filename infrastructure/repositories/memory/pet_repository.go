@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type petRecord struct {
+	id                  string
+	ownerUserId         string
+	name                string
+	breed               string
+	size                models.JobDogSize
+	birthDate           openapi_types.Date
+	medicalNotes        *string
+	feedingInstructions *string
+	temperament         []models.PetTemperament
+	createdAt           time.Time
+	updatedAt           time.Time
+}
+
+func (r petRecord) toModel() models.Pet {
+	temperament := append([]models.PetTemperament{}, r.temperament...)
+
+	return models.Pet{
+		Id:                  &r.id,
+		OwnerUserId:         &r.ownerUserId,
+		Name:                r.name,
+		Breed:               r.breed,
+		Size:                r.size,
+		BirthDate:           r.birthDate,
+		MedicalNotes:        copyString(r.medicalNotes),
+		FeedingInstructions: copyString(r.feedingInstructions),
+		Temperament:         &temperament,
+		CreatedAt:           &r.createdAt,
+		UpdatedAt:           &r.updatedAt,
+	}
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	c := *s
+	return &c
+}
+
+var _ domain.PetRepository = (*PetRepository)(nil)
+
+type PetRepository struct {
+	mu   sync.RWMutex
+	pets map[string]petRecord
+}
+
+func NewPetRepository() *PetRepository {
+	return &PetRepository{
+		pets: make(map[string]petRecord),
+	}
+}
+
+func (p *PetRepository) PostPet(ctx context.Context, pet models.Pet) (models.Pet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	created := now()
+	rec := petRecord{
+		id:        newID(),
+		createdAt: created,
+	}
+	if pet.OwnerUserId != nil {
+		rec.ownerUserId = *pet.OwnerUserId
+	}
+	rec.update(pet, created)
+	p.pets[rec.id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (p *PetRepository) GetPet(ctx context.Context, id string) (models.Pet, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	rec, ok := p.pets[id]
+	if !ok {
+		return models.Pet{}, fmt.Errorf("pet %s: %w", id, domain.ErrNotFound)
+	}
+
+	return rec.toModel(), nil
+}
+
+func (p *PetRepository) GetPets(ctx context.Context, ownerUserId string) ([]models.Pet, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var found []petRecord
+	for _, rec := range p.pets {
+		if rec.ownerUserId == ownerUserId {
+			found = append(found, rec)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].name != found[j].name {
+			return found[i].name < found[j].name
+		}
+		return found[i].id < found[j].id
+	})
+
+	pets := make([]models.Pet, 0, len(found))
+	for _, rec := range found {
+		pets = append(pets, rec.toModel())
+	}
+
+	return pets, nil
+}
+
+func (p *PetRepository) PutPet(ctx context.Context, id string, pet models.Pet) (models.Pet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rec, ok := p.pets[id]
+	if !ok {
+		return models.Pet{}, fmt.Errorf("pet %s: %w", id, domain.ErrNotFound)
+	}
+	rec.update(pet, now())
+	p.pets[id] = rec
+
+	return rec.toModel(), nil
+}
+
+// update replaces the editable fields of r with those of pet.
+func (r *petRecord) update(pet models.Pet, updated time.Time) {
+	r.name = pet.Name
+	r.breed = pet.Breed
+	r.size = pet.Size
+	r.birthDate = pet.BirthDate
+	r.medicalNotes = copyString(pet.MedicalNotes)
+	r.feedingInstructions = copyString(pet.FeedingInstructions)
+	r.temperament = nil
+	if pet.Temperament != nil {
+		r.temperament = append(r.temperament, *pet.Temperament...)
+	}
+	r.updatedAt = updated
+}
+
+func (p *PetRepository) DeletePet(ctx context.Context, id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.pets[id]; !ok {
+		return fmt.Errorf("pet %s: %w", id, domain.ErrNotFound)
+	}
+	delete(p.pets, id)
+
+	return nil
+}
+
+func (p *PetRepository) DeletePetsByOwner(ctx context.Context, ownerUserId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for id, rec := range p.pets {
+		if rec.ownerUserId == ownerUserId {
+			delete(p.pets, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bersennaidoo/agentco/domain"
+)
+
+var _ domain.SessionRepository = (*SessionRepository)(nil)
+
+type SessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[string]domain.Session
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		sessions: make(map[string]domain.Session),
+	}
+}
+
+func (s *SessionRepository) CreateSession(ctx context.Context, session domain.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[session.Id]; ok {
+		return fmt.Errorf("session %s: %w", session.Id, domain.ErrConflict)
+	}
+	s.sessions[session.Id] = session
+
+	return nil
+}
+
+func (s *SessionRepository) GetSession(ctx context.Context, id string) (domain.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return domain.Session{}, fmt.Errorf("session %s: %w", id, domain.ErrNotFound)
+	}
+
+	return session, nil
+}
+
+func (s *SessionRepository) DeleteUserSessions(ctx context.Context, userId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, session := range s.sessions {
+		if session.UserId == userId {
+			delete(s.sessions, id)
+		}
+	}
+
+	return nil
+}
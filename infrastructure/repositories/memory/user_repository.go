@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type userRecord struct {
+	id           string
+	email        string
+	fullName     string
+	passwordHash string
+	roles        []models.UserRoles
+	createdAt    time.Time
+	updatedAt    time.Time
+}
+
+func (r userRecord) toModel() models.User {
+	return models.User{
+		Id:        &r.id,
+		Email:     openapi_types.Email(r.email),
+		FullName:  r.fullName,
+		Roles:     append([]models.UserRoles(nil), r.roles...),
+		CreatedAt: &r.createdAt,
+		UpdatedAt: &r.updatedAt,
+	}
+}
+
+var _ domain.UserRepository = (*UserRepository)(nil)
+
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[string]userRecord
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users: make(map[string]userRecord),
+	}
+}
+
+func (u *UserRepository) PostUsers(ctx context.Context, user models.User, passwordHash string) (models.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	email := strings.ToLower(string(user.Email))
+	if u.emailTaken(email, "") {
+		return models.User{}, fmt.Errorf("email %s: %w", email, domain.ErrConflict)
+	}
+
+	created := now()
+	rec := userRecord{
+		id:           newID(),
+		email:        email,
+		fullName:     user.FullName,
+		passwordHash: passwordHash,
+		roles:        append([]models.UserRoles(nil), user.Roles...),
+		createdAt:    created,
+		updatedAt:    created,
+	}
+	u.users[rec.id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (u *UserRepository) GetUsersId(ctx context.Context, id string) (models.User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	rec, ok := u.users[id]
+	if !ok {
+		return models.User{}, fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+
+	return rec.toModel(), nil
+}
+
+func (u *UserRepository) PutUsersId(ctx context.Context, id string, user models.User, passwordHash string) (models.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	rec, ok := u.users[id]
+	if !ok {
+		return models.User{}, fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+
+	email := strings.ToLower(string(user.Email))
+	if u.emailTaken(email, id) {
+		return models.User{}, fmt.Errorf("email %s: %w", email, domain.ErrConflict)
+	}
+
+	rec.email = email
+	rec.fullName = user.FullName
+	rec.roles = append([]models.UserRoles(nil), user.Roles...)
+	rec.updatedAt = now()
+	if passwordHash != "" {
+		rec.passwordHash = passwordHash
+	}
+	u.users[id] = rec
+
+	return rec.toModel(), nil
+}
+
+func (u *UserRepository) DeleteUsersId(ctx context.Context, id string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.users[id]; !ok {
+		return fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+	delete(u.users, id)
+
+	return nil
+}
+
+func (u *UserRepository) GetUserCredentials(ctx context.Context, email string) (models.User, string, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	email = strings.ToLower(email)
+	for _, rec := range u.users {
+		if rec.email == email {
+			return rec.toModel(), rec.passwordHash, nil
+		}
+	}
+
+	return models.User{}, "", fmt.Errorf("user %s: %w", email, domain.ErrNotFound)
+}
+
+func (u *UserRepository) UpdatePasswordHash(ctx context.Context, id string, hash string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	rec, ok := u.users[id]
+	if !ok {
+		return fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+	rec.passwordHash = hash
+	u.users[id] = rec
+
+	return nil
+}
+
+// emailTaken must be called with u.mu held.
+func (u *UserRepository) emailTaken(email, exceptId string) bool {
+	for id, rec := range u.users {
+		if rec.email == email && id != exceptId {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,172 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const availabilityCollection = "availability"
+
+// availabilityDocument is keyed by the id of the sitter it belongs to.
+type availabilityDocument struct {
+	UserId      string                 `bson:"_id"`
+	Timezone    string                 `bson:"timezone"`
+	WeeklySlots []availabilitySlot     `bson:"weekly_slots"`
+	Blackouts   []availabilityBlackout `bson:"blackouts"`
+	Activities  []models.JobActivities `bson:"activities"`
+	DogSizes    []models.JobDogSize    `bson:"dog_sizes"`
+	UpdatedAt   time.Time              `bson:"updated_at"`
+}
+
+// availabilitySlot and availabilityBlackout mirror the fields of their
+// models, in the same order, so that they convert directly.
+type availabilitySlot struct {
+	Day   models.AvailabilitySlotDay `bson:"day"`
+	End   string                     `bson:"end"`
+	Start string                     `bson:"start"`
+}
+
+type availabilityBlackout struct {
+	EndsAt   time.Time `bson:"ends_at"`
+	Reason   *string   `bson:"reason,omitempty"`
+	StartsAt time.Time `bson:"starts_at"`
+}
+
+func (d availabilityDocument) toModel() models.Availability {
+	slots := make([]models.AvailabilitySlot, 0, len(d.WeeklySlots))
+	for _, slot := range d.WeeklySlots {
+		slots = append(slots, models.AvailabilitySlot(slot))
+	}
+	blackouts := make([]models.AvailabilityBlackout, 0, len(d.Blackouts))
+	for _, blackout := range d.Blackouts {
+		blackouts = append(blackouts, models.AvailabilityBlackout(blackout))
+	}
+	dogSizes := append([]models.JobDogSize{}, d.DogSizes...)
+
+	return models.Availability{
+		UserId:      &d.UserId,
+		Timezone:    d.Timezone,
+		WeeklySlots: slots,
+		Blackouts:   &blackouts,
+		Activities:  d.Activities,
+		DogSizes:    &dogSizes,
+		UpdatedAt:   &d.UpdatedAt,
+	}
+}
+
+var _ domain.AvailabilityRepository = (*AvailabilityRepository)(nil)
+
+type AvailabilityRepository struct {
+	db *mongo.Database
+}
+
+func NewAvailabilityRepository(db *mongo.Database) *AvailabilityRepository {
+	return &AvailabilityRepository{
+		db: db,
+	}
+}
+
+// EnsureIndexes creates the index used to find the sitters offering an
+// activity.
+func (a *AvailabilityRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := a.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "activities", Value: 1}},
+		Options: options.Index().SetName("activities"),
+	})
+	if err != nil {
+		return fmt.Errorf("creating availability indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AvailabilityRepository) GetAvailability(ctx context.Context, userId string) (models.Availability, error) {
+	var doc availabilityDocument
+	err := a.collection().FindOne(ctx, bson.D{{Key: "_id", Value: userId}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.Availability{}, fmt.Errorf("availability of user %s: %w", userId, domain.ErrNotFound)
+	}
+	if err != nil {
+		return models.Availability{}, fmt.Errorf("finding availability: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (a *AvailabilityRepository) PutAvailability(ctx context.Context, userId string, availability models.Availability) (models.Availability, error) {
+	doc := availabilityDocument{
+		UserId:      userId,
+		Timezone:    availability.Timezone,
+		WeeklySlots: make([]availabilitySlot, 0, len(availability.WeeklySlots)),
+		Blackouts:   []availabilityBlackout{},
+		Activities:  availability.Activities,
+		DogSizes:    []models.JobDogSize{},
+		UpdatedAt:   time.Now().UTC().Truncate(time.Millisecond),
+	}
+	for _, slot := range availability.WeeklySlots {
+		doc.WeeklySlots = append(doc.WeeklySlots, availabilitySlot(slot))
+	}
+	if availability.Blackouts != nil {
+		for _, blackout := range *availability.Blackouts {
+			doc.Blackouts = append(doc.Blackouts, availabilityBlackout{
+				StartsAt: blackout.StartsAt.UTC().Truncate(time.Millisecond),
+				EndsAt:   blackout.EndsAt.UTC().Truncate(time.Millisecond),
+				Reason:   blackout.Reason,
+			})
+		}
+	}
+	if availability.DogSizes != nil {
+		doc.DogSizes = append(doc.DogSizes, *availability.DogSizes...)
+	}
+
+	_, err := a.collection().ReplaceOne(ctx, bson.D{{Key: "_id", Value: userId}}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return models.Availability{}, fmt.Errorf("replacing availability: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (a *AvailabilityRepository) DeleteAvailability(ctx context.Context, userId string) error {
+	result, err := a.collection().DeleteOne(ctx, bson.D{{Key: "_id", Value: userId}})
+	if err != nil {
+		return fmt.Errorf("deleting availability: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("availability of user %s: %w", userId, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (a *AvailabilityRepository) GetAvailabilities(ctx context.Context, activities []models.JobActivities) ([]models.Availability, error) {
+	filter := bson.D{{Key: "activities", Value: bson.D{{Key: "$in", Value: activities}}}}
+	cursor, err := a.collection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("finding availability: %w", err)
+	}
+
+	var docs []availabilityDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("decoding availability: %w", err)
+	}
+
+	availabilities := make([]models.Availability, 0, len(docs))
+	for _, doc := range docs {
+		availabilities = append(availabilities, doc.toModel())
+	}
+
+	return availabilities, nil
+}
+
+func (a *AvailabilityRepository) collection() *mongo.Collection {
+	return a.db.Collection(availabilityCollection)
+}
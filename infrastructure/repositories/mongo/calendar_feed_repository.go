@@ -0,0 +1,157 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const calendarFeedsCollection = "calendar_feeds"
+
+// calendarFeedDocument is keyed by the id of the user the feed belongs to.
+type calendarFeedDocument struct {
+	UserId    string                  `bson:"_id"`
+	TokenHash string                  `bson:"token_hash"`
+	Events    []calendarEventDocument `bson:"events"`
+	Version   int                     `bson:"version"`
+}
+
+type calendarEventDocument struct {
+	JobId       string    `bson:"job_id"`
+	Sequence    int       `bson:"sequence"`
+	Fingerprint string    `bson:"fingerprint"`
+	Summary     string    `bson:"summary"`
+	StartsAt    time.Time `bson:"starts_at"`
+	EndsAt      time.Time `bson:"ends_at"`
+	Cancelled   bool      `bson:"cancelled,omitempty"`
+}
+
+func (d calendarFeedDocument) toDomain() domain.CalendarFeed {
+	feed := domain.CalendarFeed{
+		UserId:    d.UserId,
+		TokenHash: d.TokenHash,
+		Events:    make([]domain.CalendarEvent, 0, len(d.Events)),
+		Version:   d.Version,
+	}
+	for _, event := range d.Events {
+		feed.Events = append(feed.Events, domain.CalendarEvent(event))
+	}
+
+	return feed
+}
+
+var _ domain.CalendarFeedRepository = (*CalendarFeedRepository)(nil)
+
+type CalendarFeedRepository struct {
+	db *mongo.Database
+}
+
+func NewCalendarFeedRepository(db *mongo.Database) *CalendarFeedRepository {
+	return &CalendarFeedRepository{
+		db: db,
+	}
+}
+
+// EnsureIndexes creates the unique index feeds are looked up by.
+func (c *CalendarFeedRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := c.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "token_hash", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("token_hash_unique"),
+	})
+	if err != nil {
+		return fmt.Errorf("creating calendar feeds indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (c *CalendarFeedRepository) GetCalendarFeedByToken(ctx context.Context, tokenHash string) (domain.CalendarFeed, error) {
+	var doc calendarFeedDocument
+	err := c.collection().FindOne(ctx, bson.D{{Key: "token_hash", Value: tokenHash}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.CalendarFeed{}, fmt.Errorf("calendar feed: %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return domain.CalendarFeed{}, fmt.Errorf("finding calendar feed: %w", err)
+	}
+
+	return doc.toDomain(), nil
+}
+
+func (c *CalendarFeedRepository) PutCalendarToken(ctx context.Context, userId string, tokenHash string) error {
+	_, err := c.collection().UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: userId}},
+		bson.D{
+			{Key: "$set", Value: bson.D{{Key: "token_hash", Value: tokenHash}}},
+			{Key: "$setOnInsert", Value: bson.D{{Key: "events", Value: bson.A{}}, {Key: "version", Value: 0}}},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("calendar feed token: %w", domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("updating calendar token: %w", err)
+	}
+
+	return nil
+}
+
+func (c *CalendarFeedRepository) PutCalendarEvents(ctx context.Context, userId string, version int, events []domain.CalendarEvent) error {
+	docs := make([]calendarEventDocument, 0, len(events))
+	for _, event := range events {
+		event.StartsAt = event.StartsAt.UTC().Truncate(time.Millisecond)
+		event.EndsAt = event.EndsAt.UTC().Truncate(time.Millisecond)
+		docs = append(docs, calendarEventDocument(event))
+	}
+
+	// Feeds created before versions were kept have none, which null matches.
+	current := any(version)
+	if version == 0 {
+		current = bson.D{{Key: "$in", Value: bson.A{0, nil}}}
+	}
+	result, err := c.collection().UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: userId}, {Key: "version", Value: current}},
+		bson.D{
+			{Key: "$set", Value: bson.D{{Key: "events", Value: docs}}},
+			{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("updating calendar events: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		count, err := c.collection().CountDocuments(ctx, bson.D{{Key: "_id", Value: userId}})
+		if err != nil {
+			return fmt.Errorf("finding calendar feed: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("calendar feed of user %s: %w", userId, domain.ErrNotFound)
+		}
+		return fmt.Errorf("calendar feed of user %s is no longer at version %d: %w", userId, version, domain.ErrConflict)
+	}
+
+	return nil
+}
+
+func (c *CalendarFeedRepository) DeleteCalendarFeed(ctx context.Context, userId string) error {
+	result, err := c.collection().DeleteOne(ctx, bson.D{{Key: "_id", Value: userId}})
+	if err != nil {
+		return fmt.Errorf("deleting calendar feed: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("calendar feed of user %s: %w", userId, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (c *CalendarFeedRepository) collection() *mongo.Collection {
+	return c.db.Collection(calendarFeedsCollection)
+}
@@ -0,0 +1,424 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"github.com/bersennaidoo/agentco/physical/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	jobApplicationsCollection = "job_applications"
+	// schedulesCollection holds a document per sitter that accepting an
+	// application bumps, so that two transactions accepting the same sitter
+	// for different jobs write conflict rather than both passing the
+	// schedule check.
+	schedulesCollection = "schedules"
+)
+
+type jobApplicationDocument struct {
+	Id        string                      `bson:"_id"`
+	JobId     string                      `bson:"job_id"`
+	UserId    string                      `bson:"user_id"`
+	Status    models.JobApplicationStatus `bson:"status"`
+	CreatedAt time.Time                   `bson:"created_at"`
+	UpdatedAt time.Time                   `bson:"updated_at"`
+}
+
+func (d jobApplicationDocument) toModel() models.JobApplication {
+	return models.JobApplication{
+		Id:     &d.Id,
+		JobId:  &d.JobId,
+		UserId: &d.UserId,
+		Status: &d.Status,
+	}
+}
+
+var _ domain.JobApplicationRepository = (*JobApplicationRepository)(nil)
+
+// JobApplicationRepository keeps job applications in their own collection.
+// Accepting and withdrawing also update the jobs collection inside a
+// transaction, so MongoDB has to run as a replica set.
+type JobApplicationRepository struct {
+	db *mongo.Database
+}
+
+func NewJobApplicationRepository(db *mongo.Database) *JobApplicationRepository {
+	return &JobApplicationRepository{
+		db: db,
+	}
+}
+
+func (j *JobApplicationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := j.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "job_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetName("job_id_status"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("user_id_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("user_id_status_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "job_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("job_id_user_id_unique"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating job applications indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (j *JobApplicationRepository) CreateJobApplication(ctx context.Context, application models.JobApplication) (models.JobApplication, error) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	doc := jobApplicationDocument{
+		Id:        primitive.NewObjectID().Hex(),
+		Status:    models.APPLYING,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if application.JobId != nil {
+		doc.JobId = *application.JobId
+	}
+	if application.UserId != nil {
+		doc.UserId = *application.UserId
+	}
+
+	err := transaction(ctx, j.db, func(ctx mongo.SessionContext) error {
+		// Writing to the job makes a DeleteJob running at the same time
+		// write conflict, so that no application outlives its job.
+		res, err := j.db.Collection(jobsCollection).UpdateOne(ctx,
+			bson.D{{Key: "_id", Value: doc.JobId}},
+			bson.D{{Key: "$inc", Value: bson.D{{Key: "applications_version", Value: 1}}}},
+		)
+		if err != nil {
+			return fmt.Errorf("locking job: %w", err)
+		}
+		if res.MatchedCount == 0 {
+			return fmt.Errorf("job %s: %w", doc.JobId, domain.ErrNotFound)
+		}
+
+		_, err = j.collection().InsertOne(ctx, doc)
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("application to job %s: %w", doc.JobId, domain.ErrConflict)
+		}
+		if err != nil {
+			return fmt.Errorf("inserting job application: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.JobApplication{}, err
+	}
+
+	return doc.toModel(), nil
+}
+
+func (j *JobApplicationRepository) GetJobApplication(ctx context.Context, id string) (models.JobApplication, error) {
+	doc, err := j.find(ctx, id)
+	if err != nil {
+		return models.JobApplication{}, err
+	}
+
+	return doc.toModel(), nil
+}
+
+func (j *JobApplicationRepository) GetApplicationsByJobId(ctx context.Context, jobId string) ([]models.JobApplication, error) {
+	cursor, err := j.collection().Find(ctx,
+		bson.D{{Key: "job_id", Value: jobId}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("finding job applications: %w", err)
+	}
+
+	var docs []jobApplicationDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("decoding job applications: %w", err)
+	}
+
+	applications := make([]models.JobApplication, 0, len(docs))
+	for _, doc := range docs {
+		applications = append(applications, doc.toModel())
+	}
+
+	return applications, nil
+}
+
+func (j *JobApplicationRepository) GetJobApplications(ctx context.Context, query domain.JobApplicationQuery) ([]models.JobApplication, int, error) {
+	filter := bson.D{{Key: "user_id", Value: query.UserId}}
+	if query.Status != nil {
+		filter = append(filter, bson.E{Key: "status", Value: *query.Status})
+	}
+
+	total, err := j.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("counting job applications: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(query.Offset))
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit))
+	}
+
+	cursor, err := j.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("finding job applications: %w", err)
+	}
+
+	var docs []jobApplicationDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, 0, fmt.Errorf("decoding job applications: %w", err)
+	}
+
+	applications := make([]models.JobApplication, 0, len(docs))
+	for _, doc := range docs {
+		applications = append(applications, doc.toModel())
+	}
+
+	return applications, int(total), nil
+}
+
+func (j *JobApplicationRepository) AcceptJobApplication(ctx context.Context, id string, buffer time.Duration) (models.JobApplication, error) {
+	var accepted jobApplicationDocument
+
+	err := transaction(ctx, j.db, func(ctx mongo.SessionContext) error {
+		now := time.Now().UTC().Truncate(time.Millisecond)
+
+		doc, err := j.transition(ctx, id, models.APPLYING, models.ACCEPTED, now)
+		if err != nil {
+			return err
+		}
+
+		res, err := j.db.Collection(jobsCollection).UpdateOne(ctx,
+			bson.D{{Key: "_id", Value: doc.JobId}, {Key: "worker_user_id", Value: nil}},
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "worker_user_id", Value: doc.UserId},
+				{Key: "updated_at", Value: now},
+			}}},
+		)
+		if err != nil {
+			return fmt.Errorf("filling job: %w", err)
+		}
+		if res.MatchedCount == 0 {
+			return fmt.Errorf("job %s is missing or already filled: %w", doc.JobId, domain.ErrConflict)
+		}
+
+		if err := j.checkSchedule(ctx, doc, buffer, now); err != nil {
+			return err
+		}
+
+		_, err = j.collection().UpdateMany(ctx,
+			bson.D{
+				{Key: "job_id", Value: doc.JobId},
+				{Key: "status", Value: models.APPLYING},
+			},
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "status", Value: models.DENIED},
+				{Key: "updated_at", Value: now},
+			}}},
+		)
+		if err != nil {
+			return fmt.Errorf("denying other applications: %w", err)
+		}
+
+		accepted = doc
+		return nil
+	})
+	if err != nil {
+		return models.JobApplication{}, err
+	}
+
+	return accepted.toModel(), nil
+}
+
+func (j *JobApplicationRepository) DenyJobApplication(ctx context.Context, id string) (models.JobApplication, error) {
+	doc, err := j.transition(ctx, id, models.APPLYING, models.DENIED, time.Now().UTC().Truncate(time.Millisecond))
+	if err != nil {
+		return models.JobApplication{}, err
+	}
+
+	return doc.toModel(), nil
+}
+
+func (j *JobApplicationRepository) WithdrawJobApplication(ctx context.Context, id string) error {
+	return transaction(ctx, j.db, func(ctx mongo.SessionContext) error {
+		var doc jobApplicationDocument
+		err := j.collection().FindOneAndDelete(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("job application %s: %w", id, domain.ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("deleting job application: %w", err)
+		}
+
+		if doc.Status != models.ACCEPTED {
+			return nil
+		}
+
+		_, err = j.db.Collection(jobsCollection).UpdateOne(ctx,
+			bson.D{{Key: "_id", Value: doc.JobId}, {Key: "worker_user_id", Value: doc.UserId}},
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "worker_user_id", Value: nil},
+				{Key: "updated_at", Value: time.Now().UTC().Truncate(time.Millisecond)},
+			}}},
+		)
+		if err != nil {
+			return fmt.Errorf("reopening job: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (j *JobApplicationRepository) DeleteJob(ctx context.Context, jobId string, before func(ctx context.Context) error) error {
+	return transaction(ctx, j.db, func(ctx mongo.SessionContext) error {
+		if err := deleteUnfilledJob(ctx, j.db, jobId); err != nil {
+			return err
+		}
+		if before != nil {
+			if err := before(ctx); err != nil {
+				return err
+			}
+		}
+
+		_, err := j.collection().DeleteMany(ctx, bson.D{{Key: "job_id", Value: jobId}})
+		if err != nil {
+			return fmt.Errorf("deleting job applications: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// checkSchedule fails with a *domain.ScheduleConflictError when the
+// applicant of doc works another job within buffer of doc's job.
+func (j *JobApplicationRepository) checkSchedule(ctx mongo.SessionContext, doc jobApplicationDocument, buffer time.Duration, now time.Time) error {
+	var job jobDocument
+	err := j.db.Collection(jobsCollection).FindOne(ctx, bson.D{{Key: "_id", Value: doc.JobId}}).Decode(&job)
+	if err != nil {
+		return fmt.Errorf("finding job: %w", err)
+	}
+
+	window := domain.Interval{Start: job.StartsAt, End: job.EndsAt}.Pad(buffer)
+	return checkSchedule(ctx, j.db, doc.UserId, doc.JobId, window, now)
+}
+
+// checkSchedule bumps the schedule of userId, so that concurrent
+// transactions changing it write conflict, then fails with a
+// *domain.ScheduleConflictError naming the earliest job other than jobId
+// that userId works within window.
+func checkSchedule(ctx mongo.SessionContext, db *mongo.Database, userId, jobId string, window domain.Interval, now time.Time) error {
+	_, err := db.Collection(schedulesCollection).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: userId}},
+		bson.D{
+			{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+			{Key: "$set", Value: bson.D{{Key: "updated_at", Value: now}}},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("locking schedule: %w", err)
+	}
+
+	var other jobDocument
+	err = db.Collection(jobsCollection).FindOne(ctx,
+		bson.D{
+			{Key: "worker_user_id", Value: userId},
+			{Key: "_id", Value: bson.D{{Key: "$ne", Value: jobId}}},
+			{Key: "starts_at", Value: bson.D{{Key: "$lt", Value: window.End}}},
+			{Key: "ends_at", Value: bson.D{{Key: "$gt", Value: window.Start}}},
+		},
+		options.FindOne().SetSort(bson.D{{Key: "starts_at", Value: 1}}),
+	).Decode(&other)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("finding overlapping jobs: %w", err)
+	}
+
+	return &domain.ScheduleConflictError{JobId: other.Id}
+}
+
+func (j *JobApplicationRepository) find(ctx context.Context, id string) (jobApplicationDocument, error) {
+	var doc jobApplicationDocument
+	err := j.collection().FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return doc, fmt.Errorf("job application %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return doc, fmt.Errorf("finding job application: %w", err)
+	}
+
+	return doc, nil
+}
+
+// transition moves the application from one status to another, failing with
+// ErrConflict when it is not currently in the from status.
+func (j *JobApplicationRepository) transition(ctx context.Context, id string, from, to models.JobApplicationStatus, now time.Time) (jobApplicationDocument, error) {
+	var doc jobApplicationDocument
+	err := j.collection().FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: id}, {Key: "status", Value: from}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: to},
+			{Key: "updated_at", Value: now},
+		}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		current, err := j.find(ctx, id)
+		if err != nil {
+			return doc, err
+		}
+		return doc, fmt.Errorf("job application %s is %s, not %s: %w", id, current.Status, from, domain.ErrConflict)
+	}
+	if err != nil {
+		return doc, fmt.Errorf("updating job application: %w", err)
+	}
+
+	return doc, nil
+}
+
+// transaction runs fn in a transaction, retrying it on transient errors.
+func transaction(ctx context.Context, db *mongo.Database, fn func(ctx mongo.SessionContext) error) error {
+	session, err := db.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("starting session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	attempt := 0
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (any, error) {
+		attempt++
+		if attempt > 1 {
+			logging.FromContext(ctx).Debug("Retrying transaction", slog.Int("attempt", attempt))
+		}
+		return nil, fn(ctx)
+	})
+
+	return err
+}
+
+func (j *JobApplicationRepository) collection() *mongo.Collection {
+	return j.db.Collection(jobApplicationsCollection)
+}
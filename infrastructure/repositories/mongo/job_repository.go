@@ -0,0 +1,469 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const jobsCollection = "jobs"
+
+type dogDocument struct {
+	Name     *string           `bson:"name,omitempty"`
+	Breed    string            `bson:"breed"`
+	Size     models.JobDogSize `bson:"size"`
+	YearsOld int               `bson:"years_old"`
+}
+
+type jobDocument struct {
+	Id            string                 `bson:"_id"`
+	CreatorUserId string                 `bson:"creator_user_id"`
+	WorkerUserId  *string                `bson:"worker_user_id"`
+	Description   string                 `bson:"description"`
+	Dog           *dogDocument           `bson:"dog,omitempty"`
+	Dogs          []dogDocument          `bson:"dogs,omitempty"`
+	PetIds        []string               `bson:"pet_ids,omitempty"`
+	Activities    []models.JobActivities `bson:"activities"`
+	StartsAt      time.Time              `bson:"starts_at"`
+	EndsAt        time.Time              `bson:"ends_at"`
+	SeriesId      *string                `bson:"series_id,omitempty"`
+	RecurrenceId  *time.Time             `bson:"recurrence_id,omitempty"`
+	Detached      bool                   `bson:"detached,omitempty"`
+	CreatedAt     time.Time              `bson:"created_at"`
+	UpdatedAt     time.Time              `bson:"updated_at"`
+}
+
+func newDogDocument(dog *models.JobDog) *dogDocument {
+	if dog == nil {
+		return nil
+	}
+
+	return &dogDocument{
+		Name:     dog.Name,
+		Breed:    dog.Breed,
+		Size:     dog.Size,
+		YearsOld: dog.YearsOld,
+	}
+}
+
+// newDogDocuments returns every dog of job, so that a job with only an
+// inline dog can be found by its size through dogs as well.
+func newDogDocuments(job models.Job) []dogDocument {
+	dogs := domain.JobDogs(job)
+	if dogs == nil {
+		return nil
+	}
+
+	docs := make([]dogDocument, 0, len(dogs))
+	for _, dog := range dogs {
+		docs = append(docs, *newDogDocument(&dog))
+	}
+
+	return docs
+}
+
+func (d dogDocument) toModel() models.JobDog {
+	return models.JobDog{
+		Name:     d.Name,
+		Breed:    d.Breed,
+		Size:     d.Size,
+		YearsOld: d.YearsOld,
+	}
+}
+
+func (d jobDocument) toModel() models.Job {
+	job := models.Job{
+		Id:            &d.Id,
+		CreatorUserId: &d.CreatorUserId,
+		WorkerUserId:  d.WorkerUserId,
+		Description:   d.Description,
+		Activities:    d.Activities,
+		StartsAt:      d.StartsAt,
+		EndsAt:        d.EndsAt,
+		CreatedAt:     &d.CreatedAt,
+		UpdatedAt:     &d.UpdatedAt,
+	}
+	if d.SeriesId != nil {
+		job.SeriesId = d.SeriesId
+		job.RecurrenceId = d.RecurrenceId
+		job.Detached = &d.Detached
+	}
+	if d.Dog != nil {
+		dog := d.Dog.toModel()
+		job.Dog = &dog
+	}
+	if d.Dogs != nil {
+		dogs := make([]models.JobDog, 0, len(d.Dogs))
+		for _, dog := range d.Dogs {
+			dogs = append(dogs, dog.toModel())
+		}
+		job.Dogs = &dogs
+	}
+	if d.PetIds != nil {
+		petIds := d.PetIds
+		job.PetIds = &petIds
+	}
+
+	return job
+}
+
+var _ domain.JobRepository = (*JobRepository)(nil)
+
+type JobRepository struct {
+	db *mongo.Database
+}
+
+func NewJobRepository(db *mongo.Database) *JobRepository {
+	return &JobRepository{
+		db: db,
+	}
+}
+
+// EnsureIndexes creates the indexes backing the GetJobs filters. Each filter
+// index ends in starts_at so the default sort can use it as well.
+//
+// The dog size filter reads dogs, so jobs saved before dogs was added first
+// get their dog copied into it, and the index on the old dog.size is dropped.
+func (j *JobRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := j.collection().UpdateMany(ctx,
+		bson.D{
+			{Key: "dog", Value: bson.D{{Key: "$type", Value: "object"}}},
+			{Key: "dogs", Value: bson.D{{Key: "$exists", Value: false}}},
+		},
+		mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: "dogs", Value: bson.A{"$dog"}}}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("filling in dogs of jobs: %w", err)
+	}
+
+	_, err = j.collection().Indexes().DropOne(ctx, "dog_size_starts_at")
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "IndexNotFound") {
+		return fmt.Errorf("dropping jobs index dog_size_starts_at: %w", err)
+	}
+
+	_, err = j.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "creator_user_id", Value: 1}},
+			Options: options.Index().SetName("creator_user_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "starts_at", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("starts_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "ends_at", Value: 1}},
+			Options: options.Index().SetName("ends_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "activities", Value: 1}, {Key: "starts_at", Value: 1}},
+			Options: options.Index().SetName("activities_starts_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "dogs.size", Value: 1}, {Key: "starts_at", Value: 1}},
+			Options: options.Index().SetName("dogs_size_starts_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "worker_user_id", Value: 1}, {Key: "starts_at", Value: 1}},
+			Options: options.Index().SetName("worker_user_id_starts_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_user_id", Value: 1}, {Key: "starts_at", Value: 1}},
+			Options: options.Index().SetName("creator_user_id_starts_at"),
+		},
+		{
+			Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "recurrence_id", Value: 1}},
+			Options: options.Index().
+				SetName("series_id_recurrence_id_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.D{{Key: "series_id", Value: bson.D{{Key: "$exists", Value: true}}}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating jobs indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (j *JobRepository) PostJobs(ctx context.Context, job models.Job) (models.Job, error) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	doc := jobDocument{
+		Id:          primitive.NewObjectID().Hex(),
+		Description: job.Description,
+		Dog:         newDogDocument(job.Dog),
+		Dogs:        newDogDocuments(job),
+		Activities:  job.Activities,
+		StartsAt:    job.StartsAt.UTC().Truncate(time.Millisecond),
+		EndsAt:      job.EndsAt.UTC().Truncate(time.Millisecond),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if job.PetIds != nil {
+		doc.PetIds = *job.PetIds
+	}
+	if job.CreatorUserId != nil {
+		doc.CreatorUserId = *job.CreatorUserId
+	}
+	if job.SeriesId != nil && job.RecurrenceId != nil {
+		recurrenceId := job.RecurrenceId.UTC().Truncate(time.Millisecond)
+		doc.SeriesId = job.SeriesId
+		doc.RecurrenceId = &recurrenceId
+	}
+
+	_, err := j.collection().InsertOne(ctx, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.Job{}, fmt.Errorf("occurrence %s of series %s: %w", doc.RecurrenceId, *doc.SeriesId, domain.ErrConflict)
+	}
+	if err != nil {
+		return models.Job{}, fmt.Errorf("inserting job: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (j *JobRepository) GetJobsId(ctx context.Context, id string) (models.Job, error) {
+	var doc jobDocument
+	err := j.collection().FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.Job{}, fmt.Errorf("job %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return models.Job{}, fmt.Errorf("finding job: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// PutJobsId marks every job it edits as detached, which only means
+// something for occurrences of a series.
+func (j *JobRepository) PutJobsId(ctx context.Context, id string, job models.Job, buffer time.Duration) (models.Job, error) {
+	set := append(editableJobFields(job), bson.E{Key: "detached", Value: true})
+
+	return j.update(ctx, id, job, set, buffer, func(doc jobDocument) error { return nil })
+}
+
+func (j *JobRepository) UpdateOccurrence(ctx context.Context, id string, job models.Job, buffer time.Duration) (models.Job, error) {
+	return j.update(ctx, id, job, editableJobFields(job), buffer, func(doc jobDocument) error {
+		if doc.SeriesId == nil || doc.Detached {
+			return fmt.Errorf("job %s is not an attached occurrence: %w", id, domain.ErrConflict)
+		}
+		return nil
+	})
+}
+
+// update sets the fields of the job id to set when check passes on the job
+// as stored. A job with a worker that job moves has the worker's schedule
+// checked in the same transaction, so that it cannot race an acceptance.
+func (j *JobRepository) update(ctx context.Context, id string, job models.Job, set bson.D, buffer time.Duration, check func(doc jobDocument) error) (models.Job, error) {
+	var doc jobDocument
+	err := transaction(ctx, j.db, func(ctx mongo.SessionContext) error {
+		var current jobDocument
+		err := j.collection().FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&current)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("job %s: %w", id, domain.ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("finding job: %w", err)
+		}
+		if err := check(current); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		interval := domain.Interval{
+			Start: job.StartsAt.UTC().Truncate(time.Millisecond),
+			End:   job.EndsAt.UTC().Truncate(time.Millisecond),
+		}
+		moved := !interval.Start.Equal(current.StartsAt) || !interval.End.Equal(current.EndsAt)
+		if current.WorkerUserId != nil && moved {
+			if err := checkSchedule(ctx, j.db, *current.WorkerUserId, id, interval.Pad(buffer), now); err != nil {
+				return err
+			}
+		}
+
+		err = j.collection().FindOneAndUpdate(ctx,
+			bson.D{{Key: "_id", Value: id}},
+			bson.D{{Key: "$set", Value: set}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&doc)
+		if err != nil {
+			return fmt.Errorf("updating job: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.Job{}, err
+	}
+
+	return doc.toModel(), nil
+}
+
+func editableJobFields(job models.Job) bson.D {
+	return bson.D{
+		{Key: "description", Value: job.Description},
+		{Key: "dog", Value: newDogDocument(job.Dog)},
+		{Key: "dogs", Value: newDogDocuments(job)},
+		{Key: "pet_ids", Value: job.PetIds},
+		{Key: "activities", Value: job.Activities},
+		{Key: "starts_at", Value: job.StartsAt.UTC().Truncate(time.Millisecond)},
+		{Key: "ends_at", Value: job.EndsAt.UTC().Truncate(time.Millisecond)},
+		{Key: "updated_at", Value: time.Now().UTC().Truncate(time.Millisecond)},
+	}
+}
+
+func (j *JobRepository) DeleteJobsId(ctx context.Context, id string) error {
+	return deleteUnfilledJob(ctx, j.db, id)
+}
+
+// deleteUnfilledJob deletes the job id unless it has a worker.
+func deleteUnfilledJob(ctx context.Context, db *mongo.Database, id string) error {
+	jobs := db.Collection(jobsCollection)
+	res, err := jobs.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}, {Key: "worker_user_id", Value: nil}})
+	if err != nil {
+		return fmt.Errorf("deleting job: %w", err)
+	}
+	if res.DeletedCount > 0 {
+		return nil
+	}
+
+	n, err := jobs.CountDocuments(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return fmt.Errorf("counting jobs: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("job %s: %w", id, domain.ErrNotFound)
+	}
+
+	return fmt.Errorf("job %s has an accepted application: %w", id, domain.ErrConflict)
+}
+
+func (j *JobRepository) GetJobs(ctx context.Context, query domain.JobQuery) ([]models.Job, int, error) {
+	filter := jobFilter(query)
+
+	total, err := j.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("counting jobs: %w", err)
+	}
+
+	sortField := string(models.StartsAt)
+	if query.SortBy != "" {
+		sortField = string(query.SortBy)
+	}
+	direction := 1
+	if query.Descending {
+		direction = -1
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}}).
+		SetSkip(int64(query.Offset))
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit))
+	}
+
+	cursor, err := j.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("finding jobs: %w", err)
+	}
+
+	var docs []jobDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, 0, fmt.Errorf("decoding jobs: %w", err)
+	}
+
+	jobs := make([]models.Job, 0, len(docs))
+	for _, doc := range docs {
+		jobs = append(jobs, doc.toModel())
+	}
+
+	return jobs, int(total), nil
+}
+
+func jobFilter(query domain.JobQuery) bson.D {
+	filter := bson.D{}
+
+	if len(query.Activities) > 0 {
+		filter = append(filter, bson.E{Key: "activities", Value: bson.D{{Key: "$all", Value: query.Activities}}})
+	}
+	if query.DogSize != nil {
+		filter = append(filter, bson.E{Key: "dogs.size", Value: *query.DogSize})
+	}
+	var startsAt, endsAt bson.D
+	if query.StartsAfter != nil {
+		startsAt = append(startsAt, bson.E{Key: "$gte", Value: query.StartsAfter.UTC()})
+	}
+	if query.StartsBefore != nil {
+		startsAt = append(startsAt, bson.E{Key: "$lt", Value: query.StartsBefore.UTC()})
+	}
+	if query.EndsBefore != nil {
+		endsAt = append(endsAt, bson.E{Key: "$lte", Value: query.EndsBefore.UTC()})
+	}
+	if query.EndsAfter != nil {
+		endsAt = append(endsAt, bson.E{Key: "$gt", Value: query.EndsAfter.UTC()})
+	}
+	if startsAt != nil {
+		filter = append(filter, bson.E{Key: "starts_at", Value: startsAt})
+	}
+	if endsAt != nil {
+		filter = append(filter, bson.E{Key: "ends_at", Value: endsAt})
+	}
+	if query.Open != nil {
+		if *query.Open {
+			filter = append(filter, bson.E{Key: "worker_user_id", Value: nil})
+		} else {
+			filter = append(filter, bson.E{Key: "worker_user_id", Value: bson.D{{Key: "$ne", Value: nil}}})
+		}
+	}
+	if query.CreatorUserId != nil {
+		filter = append(filter, bson.E{Key: "creator_user_id", Value: *query.CreatorUserId})
+	}
+	if query.WorkerUserId != nil {
+		filter = append(filter, bson.E{Key: "worker_user_id", Value: *query.WorkerUserId})
+	}
+	if query.WorkerUserIds != nil {
+		filter = append(filter, bson.E{Key: "worker_user_id", Value: bson.D{{Key: "$in", Value: query.WorkerUserIds}}})
+	}
+	if query.ParticipantUserId != nil {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "creator_user_id", Value: *query.ParticipantUserId}},
+			bson.D{{Key: "worker_user_id", Value: *query.ParticipantUserId}},
+		}})
+	}
+	if query.SeriesId != nil {
+		filter = append(filter, bson.E{Key: "series_id", Value: *query.SeriesId})
+	}
+	if query.RecurringAfter != nil {
+		filter = append(filter, bson.E{Key: "recurrence_id", Value: bson.D{{Key: "$gte", Value: query.RecurringAfter.UTC()}}})
+	}
+	if len(query.Avoid) > 0 {
+		overlapping := make(bson.A, 0, len(query.Avoid))
+		for _, busy := range query.Avoid {
+			overlapping = append(overlapping, bson.D{
+				{Key: "starts_at", Value: bson.D{{Key: "$lt", Value: busy.End.UTC()}}},
+				{Key: "ends_at", Value: bson.D{{Key: "$gt", Value: busy.Start.UTC()}}},
+			})
+		}
+		filter = append(filter, bson.E{Key: "$nor", Value: overlapping})
+	}
+
+	return filter
+}
+
+func (j *JobRepository) collection() *mongo.Collection {
+	return j.db.Collection(jobsCollection)
+}
@@ -0,0 +1,247 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const jobSeriesCollection = "job_series"
+
+// jobSeriesDocument keeps exceptions as "2006-01-02" strings, since they are
+// dates without a time zone.
+type jobSeriesDocument struct {
+	Id                string                 `bson:"_id"`
+	CreatorUserId     string                 `bson:"creator_user_id"`
+	Rrule             string                 `bson:"rrule"`
+	Timezone          string                 `bson:"timezone"`
+	StartsAt          time.Time              `bson:"starts_at"`
+	EndsAt            time.Time              `bson:"ends_at"`
+	Exceptions        []string               `bson:"exceptions"`
+	Description       string                 `bson:"description"`
+	Dog               *dogDocument           `bson:"dog,omitempty"`
+	Activities        []models.JobActivities `bson:"activities"`
+	ApplicantUserIds  []string               `bson:"applicant_user_ids"`
+	MaterializedUntil time.Time              `bson:"materialized_until"`
+	CreatedAt         time.Time              `bson:"created_at"`
+	UpdatedAt         time.Time              `bson:"updated_at"`
+}
+
+func (d jobSeriesDocument) toModel() models.JobSeries {
+	exceptions := make([]openapi_types.Date, 0, len(d.Exceptions))
+	for _, s := range d.Exceptions {
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			continue
+		}
+		exceptions = append(exceptions, openapi_types.Date{Time: t})
+	}
+	applicants := append([]string{}, d.ApplicantUserIds...)
+
+	series := models.JobSeries{
+		Id:               &d.Id,
+		CreatorUserId:    &d.CreatorUserId,
+		Rrule:            d.Rrule,
+		Timezone:         d.Timezone,
+		StartsAt:         d.StartsAt,
+		EndsAt:           d.EndsAt,
+		Exceptions:       &exceptions,
+		Description:      d.Description,
+		Activities:       d.Activities,
+		ApplicantUserIds: &applicants,
+		CreatedAt:        &d.CreatedAt,
+		UpdatedAt:        &d.UpdatedAt,
+	}
+	if !d.MaterializedUntil.IsZero() {
+		series.MaterializedUntil = &d.MaterializedUntil
+	}
+	if d.Dog != nil {
+		series.Dog = &models.JobDog{
+			Name:     d.Dog.Name,
+			Breed:    d.Dog.Breed,
+			Size:     d.Dog.Size,
+			YearsOld: d.Dog.YearsOld,
+		}
+	}
+
+	return series
+}
+
+func exceptionStrings(series models.JobSeries) []string {
+	exceptions := []string{}
+	if series.Exceptions != nil {
+		for _, date := range *series.Exceptions {
+			exceptions = append(exceptions, date.String())
+		}
+	}
+
+	return exceptions
+}
+
+var _ domain.JobSeriesRepository = (*JobSeriesRepository)(nil)
+
+type JobSeriesRepository struct {
+	db *mongo.Database
+}
+
+func NewJobSeriesRepository(db *mongo.Database) *JobSeriesRepository {
+	return &JobSeriesRepository{
+		db: db,
+	}
+}
+
+// EnsureIndexes creates the index the materializer finds due series by.
+func (j *JobSeriesRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := j.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "materialized_until", Value: 1}},
+		Options: options.Index().SetName("materialized_until"),
+	})
+	if err != nil {
+		return fmt.Errorf("creating job series indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (j *JobSeriesRepository) PostJobSeries(ctx context.Context, series models.JobSeries) (models.JobSeries, error) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	doc := jobSeriesDocument{
+		Id:               primitive.NewObjectID().Hex(),
+		Rrule:            series.Rrule,
+		Timezone:         series.Timezone,
+		StartsAt:         series.StartsAt.UTC().Truncate(time.Millisecond),
+		EndsAt:           series.EndsAt.UTC().Truncate(time.Millisecond),
+		Exceptions:       exceptionStrings(series),
+		Description:      series.Description,
+		Dog:              newDogDocument(series.Dog),
+		Activities:       series.Activities,
+		ApplicantUserIds: []string{},
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if series.CreatorUserId != nil {
+		doc.CreatorUserId = *series.CreatorUserId
+	}
+
+	if _, err := j.collection().InsertOne(ctx, doc); err != nil {
+		return models.JobSeries{}, fmt.Errorf("inserting job series: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (j *JobSeriesRepository) GetJobSeries(ctx context.Context, id string) (models.JobSeries, error) {
+	var doc jobSeriesDocument
+	err := j.collection().FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.JobSeries{}, fmt.Errorf("job series %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return models.JobSeries{}, fmt.Errorf("finding job series: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (j *JobSeriesRepository) PutJobSeries(ctx context.Context, id string, series models.JobSeries) (models.JobSeries, error) {
+	set := bson.D{
+		{Key: "rrule", Value: series.Rrule},
+		{Key: "timezone", Value: series.Timezone},
+		{Key: "starts_at", Value: series.StartsAt.UTC().Truncate(time.Millisecond)},
+		{Key: "ends_at", Value: series.EndsAt.UTC().Truncate(time.Millisecond)},
+		{Key: "exceptions", Value: exceptionStrings(series)},
+		{Key: "description", Value: series.Description},
+		{Key: "dog", Value: newDogDocument(series.Dog)},
+		{Key: "activities", Value: series.Activities},
+		{Key: "updated_at", Value: time.Now().UTC().Truncate(time.Millisecond)},
+	}
+
+	var doc jobSeriesDocument
+	err := j.collection().FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: id}},
+		bson.D{{Key: "$set", Value: set}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.JobSeries{}, fmt.Errorf("job series %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return models.JobSeries{}, fmt.Errorf("updating job series: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (j *JobSeriesRepository) AddJobSeriesException(ctx context.Context, id string, date openapi_types.Date) error {
+	return j.addToSet(ctx, id, "exceptions", date.String())
+}
+
+func (j *JobSeriesRepository) AddJobSeriesApplicant(ctx context.Context, id string, userId string) error {
+	return j.addToSet(ctx, id, "applicant_user_ids", userId)
+}
+
+func (j *JobSeriesRepository) addToSet(ctx context.Context, id, field string, value any) error {
+	res, err := j.collection().UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: id}},
+		bson.D{
+			{Key: "$addToSet", Value: bson.D{{Key: field, Value: value}}},
+			{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now().UTC().Truncate(time.Millisecond)}}},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("updating job series: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("job series %s: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (j *JobSeriesRepository) SetJobSeriesMaterializedUntil(ctx context.Context, id string, until time.Time) error {
+	res, err := j.collection().UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: id}},
+		bson.D{{Key: "$max", Value: bson.D{{Key: "materialized_until", Value: until.UTC().Truncate(time.Millisecond)}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("updating job series: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("job series %s: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (j *JobSeriesRepository) GetJobSeriesDue(ctx context.Context, before time.Time) ([]models.JobSeries, error) {
+	cursor, err := j.collection().Find(ctx, bson.D{{Key: "materialized_until", Value: bson.D{{Key: "$lt", Value: before.UTC()}}}})
+	if err != nil {
+		return nil, fmt.Errorf("finding job series: %w", err)
+	}
+
+	var docs []jobSeriesDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("decoding job series: %w", err)
+	}
+
+	series := make([]models.JobSeries, 0, len(docs))
+	for _, doc := range docs {
+		series = append(series, doc.toModel())
+	}
+
+	return series, nil
+}
+
+func (j *JobSeriesRepository) collection() *mongo.Collection {
+	return j.db.Collection(jobSeriesCollection)
+}
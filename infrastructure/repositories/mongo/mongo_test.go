@@ -0,0 +1,100 @@
+package mongo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/infrastructure/repositories/repotest"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestConformance runs against the MongoDB named by AGENTCO_TEST_MONGO_URI,
+// using a throwaway database per test. The server must be a replica set for
+// the job application transactions.
+func TestConformance(t *testing.T) {
+	uri := os.Getenv("AGENTCO_TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("AGENTCO_TEST_MONGO_URI is not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect(ctx) })
+
+	newDB := func(t *testing.T) *mongo.Database {
+		db := client.Database("agentco_test_" + primitive.NewObjectID().Hex())
+		t.Cleanup(func() { db.Drop(ctx) })
+		return db
+	}
+
+	repotest.Run(t, repotest.Factory{
+		Users: func(t *testing.T) domain.UserRepository {
+			repo := NewUserRepository(newDB(t))
+			if err := repo.EnsureIndexes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			return repo
+		},
+		Jobs: func(t *testing.T) domain.JobRepository {
+			repo := NewJobRepository(newDB(t))
+			if err := repo.EnsureIndexes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			return repo
+		},
+		JobApplications: func(t *testing.T) (domain.JobRepository, domain.JobApplicationRepository) {
+			db := newDB(t)
+			jobs := NewJobRepository(db)
+			if err := jobs.EnsureIndexes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			repo := NewJobApplicationRepository(db)
+			if err := repo.EnsureIndexes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			return jobs, repo
+		},
+		Sessions: func(t *testing.T) domain.SessionRepository {
+			repo := NewSessionRepository(newDB(t))
+			if err := repo.EnsureIndexes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			return repo
+		},
+		JobSeries: func(t *testing.T) domain.JobSeriesRepository {
+			repo := NewJobSeriesRepository(newDB(t))
+			if err := repo.EnsureIndexes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			return repo
+		},
+		Availability: func(t *testing.T) domain.AvailabilityRepository {
+			repo := NewAvailabilityRepository(newDB(t))
+			if err := repo.EnsureIndexes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			return repo
+		},
+		CalendarFeeds: func(t *testing.T) domain.CalendarFeedRepository {
+			repo := NewCalendarFeedRepository(newDB(t))
+			if err := repo.EnsureIndexes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			return repo
+		},
+		Pets: func(t *testing.T) domain.PetRepository {
+			repo := NewPetRepository(newDB(t))
+			if err := repo.EnsureIndexes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			return repo
+		},
+	})
+}
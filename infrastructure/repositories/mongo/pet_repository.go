@@ -0,0 +1,201 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const petsCollection = "pets"
+
+// petDocument keeps the birth date as a "2006-01-02" string, like the
+// exceptions of a job series.
+type petDocument struct {
+	Id                  string                  `bson:"_id"`
+	OwnerUserId         string                  `bson:"owner_user_id"`
+	Name                string                  `bson:"name"`
+	Breed               string                  `bson:"breed"`
+	Size                models.JobDogSize       `bson:"size"`
+	BirthDate           string                  `bson:"birth_date"`
+	MedicalNotes        *string                 `bson:"medical_notes,omitempty"`
+	FeedingInstructions *string                 `bson:"feeding_instructions,omitempty"`
+	Temperament         []models.PetTemperament `bson:"temperament"`
+	CreatedAt           time.Time               `bson:"created_at"`
+	UpdatedAt           time.Time               `bson:"updated_at"`
+}
+
+func (d petDocument) toModel() models.Pet {
+	birthDate, _ := time.Parse(time.DateOnly, d.BirthDate)
+	temperament := append([]models.PetTemperament{}, d.Temperament...)
+
+	return models.Pet{
+		Id:                  &d.Id,
+		OwnerUserId:         &d.OwnerUserId,
+		Name:                d.Name,
+		Breed:               d.Breed,
+		Size:                d.Size,
+		BirthDate:           openapi_types.Date{Time: birthDate},
+		MedicalNotes:        d.MedicalNotes,
+		FeedingInstructions: d.FeedingInstructions,
+		Temperament:         &temperament,
+		CreatedAt:           &d.CreatedAt,
+		UpdatedAt:           &d.UpdatedAt,
+	}
+}
+
+func petTemperament(pet models.Pet) []models.PetTemperament {
+	temperament := []models.PetTemperament{}
+	if pet.Temperament != nil {
+		temperament = append(temperament, *pet.Temperament...)
+	}
+
+	return temperament
+}
+
+var _ domain.PetRepository = (*PetRepository)(nil)
+
+type PetRepository struct {
+	db *mongo.Database
+}
+
+func NewPetRepository(db *mongo.Database) *PetRepository {
+	return &PetRepository{
+		db: db,
+	}
+}
+
+// EnsureIndexes creates the index used to list the pets of an owner by name.
+func (p *PetRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := p.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "owner_user_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetName("owner_user_id_name"),
+	})
+	if err != nil {
+		return fmt.Errorf("creating pets indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PetRepository) PostPet(ctx context.Context, pet models.Pet) (models.Pet, error) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	doc := petDocument{
+		Id:                  primitive.NewObjectID().Hex(),
+		Name:                pet.Name,
+		Breed:               pet.Breed,
+		Size:                pet.Size,
+		BirthDate:           pet.BirthDate.String(),
+		MedicalNotes:        pet.MedicalNotes,
+		FeedingInstructions: pet.FeedingInstructions,
+		Temperament:         petTemperament(pet),
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if pet.OwnerUserId != nil {
+		doc.OwnerUserId = *pet.OwnerUserId
+	}
+
+	if _, err := p.collection().InsertOne(ctx, doc); err != nil {
+		return models.Pet{}, fmt.Errorf("inserting pet: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (p *PetRepository) GetPet(ctx context.Context, id string) (models.Pet, error) {
+	var doc petDocument
+	err := p.collection().FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.Pet{}, fmt.Errorf("pet %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return models.Pet{}, fmt.Errorf("finding pet: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (p *PetRepository) GetPets(ctx context.Context, ownerUserId string) ([]models.Pet, error) {
+	cursor, err := p.collection().Find(ctx,
+		bson.D{{Key: "owner_user_id", Value: ownerUserId}},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("finding pets: %w", err)
+	}
+
+	var docs []petDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("decoding pets: %w", err)
+	}
+
+	pets := make([]models.Pet, 0, len(docs))
+	for _, doc := range docs {
+		pets = append(pets, doc.toModel())
+	}
+
+	return pets, nil
+}
+
+func (p *PetRepository) PutPet(ctx context.Context, id string, pet models.Pet) (models.Pet, error) {
+	set := bson.D{
+		{Key: "name", Value: pet.Name},
+		{Key: "breed", Value: pet.Breed},
+		{Key: "size", Value: pet.Size},
+		{Key: "birth_date", Value: pet.BirthDate.String()},
+		{Key: "medical_notes", Value: pet.MedicalNotes},
+		{Key: "feeding_instructions", Value: pet.FeedingInstructions},
+		{Key: "temperament", Value: petTemperament(pet)},
+		{Key: "updated_at", Value: time.Now().UTC().Truncate(time.Millisecond)},
+	}
+
+	var doc petDocument
+	err := p.collection().FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: id}},
+		bson.D{{Key: "$set", Value: set}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.Pet{}, fmt.Errorf("pet %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return models.Pet{}, fmt.Errorf("updating pet: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (p *PetRepository) DeletePet(ctx context.Context, id string) error {
+	res, err := p.collection().DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return fmt.Errorf("deleting pet: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("pet %s: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (p *PetRepository) DeletePetsByOwner(ctx context.Context, ownerUserId string) error {
+	_, err := p.collection().DeleteMany(ctx, bson.D{{Key: "owner_user_id", Value: ownerUserId}})
+	if err != nil {
+		return fmt.Errorf("deleting pets: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PetRepository) collection() *mongo.Collection {
+	return p.db.Collection(petsCollection)
+}
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const sessionsCollection = "sessions"
+
+type sessionDocument struct {
+	Id        string    `bson:"_id"`
+	UserId    string    `bson:"user_id"`
+	CreatedAt time.Time `bson:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+var _ domain.SessionRepository = (*SessionRepository)(nil)
+
+type SessionRepository struct {
+	db *mongo.Database
+}
+
+func NewSessionRepository(db *mongo.Database) *SessionRepository {
+	return &SessionRepository{
+		db: db,
+	}
+}
+
+// EnsureIndexes creates a TTL index so that Mongo removes expired sessions.
+func (s *SessionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0).SetName("expires_at_ttl"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating sessions indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SessionRepository) CreateSession(ctx context.Context, session domain.Session) error {
+	_, err := s.collection().InsertOne(ctx, sessionDocument(session))
+	if err != nil {
+		return fmt.Errorf("inserting session: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SessionRepository) GetSession(ctx context.Context, id string) (domain.Session, error) {
+	var doc sessionDocument
+	err := s.collection().FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Session{}, fmt.Errorf("session %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return domain.Session{}, fmt.Errorf("finding session: %w", err)
+	}
+
+	return domain.Session(doc), nil
+}
+
+func (s *SessionRepository) DeleteUserSessions(ctx context.Context, userId string) error {
+	_, err := s.collection().DeleteMany(ctx, bson.D{{Key: "user_id", Value: userId}})
+	if err != nil {
+		return fmt.Errorf("deleting sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SessionRepository) collection() *mongo.Collection {
+	return s.db.Collection(sessionsCollection)
+}
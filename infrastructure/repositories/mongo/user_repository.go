@@ -1,18 +1,180 @@
 package mongo
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const usersCollection = "users"
+
+type userDocument struct {
+	Id           string             `bson:"_id"`
+	Email        string             `bson:"email"`
+	FullName     string             `bson:"full_name"`
+	PasswordHash string             `bson:"password_hash,omitempty"`
+	Roles        []models.UserRoles `bson:"roles"`
+	CreatedAt    time.Time          `bson:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at"`
+}
+
+// toModel converts the document to the API model. The password hash is never
+// copied onto models.User.
+func (d userDocument) toModel() models.User {
+	return models.User{
+		Id:        &d.Id,
+		Email:     openapi_types.Email(d.Email),
+		FullName:  d.FullName,
+		Roles:     d.Roles,
+		CreatedAt: &d.CreatedAt,
+		UpdatedAt: &d.UpdatedAt,
+	}
+}
+
+var _ domain.UserRepository = (*UserRepository)(nil)
+
 type UserRepository struct {
-	client *mongo.Client
+	db *mongo.Database
 }
 
-func NewUserRepository(client *mongo.Client) *UserRepository {
+func NewUserRepository(db *mongo.Database) *UserRepository {
 	return &UserRepository{
-		client: client,
+		db: db,
+	}
+}
+
+// EnsureIndexes creates the indexes the users collection relies on, most
+// importantly the unique index that rejects duplicate emails.
+func (u *UserRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := u.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("email_unique"),
+	})
+	if err != nil {
+		return fmt.Errorf("creating users indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (u *UserRepository) PostUsers(ctx context.Context, user models.User, passwordHash string) (models.User, error) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	doc := userDocument{
+		Id:           primitive.NewObjectID().Hex(),
+		Email:        strings.ToLower(string(user.Email)),
+		FullName:     user.FullName,
+		PasswordHash: passwordHash,
+		Roles:        user.Roles,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	_, err := u.collection().InsertOne(ctx, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.User{}, fmt.Errorf("email %s: %w", doc.Email, domain.ErrConflict)
+	}
+	if err != nil {
+		return models.User{}, fmt.Errorf("inserting user: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+func (u *UserRepository) GetUsersId(ctx context.Context, id string) (models.User, error) {
+	var doc userDocument
+	err := u.collection().FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.User{}, fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return models.User{}, fmt.Errorf("finding user: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// PutUsersId replaces the editable fields of the user. An empty passwordHash
+// leaves the stored password unchanged.
+func (u *UserRepository) PutUsersId(ctx context.Context, id string, user models.User, passwordHash string) (models.User, error) {
+	set := bson.D{
+		{Key: "email", Value: strings.ToLower(string(user.Email))},
+		{Key: "full_name", Value: user.FullName},
+		{Key: "roles", Value: user.Roles},
+		{Key: "updated_at", Value: time.Now().UTC().Truncate(time.Millisecond)},
+	}
+	if passwordHash != "" {
+		set = append(set, bson.E{Key: "password_hash", Value: passwordHash})
+	}
+
+	var doc userDocument
+	err := u.collection().FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: id}},
+		bson.D{{Key: "$set", Value: set}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.User{}, fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return models.User{}, fmt.Errorf("email %s: %w", user.Email, domain.ErrConflict)
 	}
+	if err != nil {
+		return models.User{}, fmt.Errorf("updating user: %w", err)
+	}
+
+	return doc.toModel(), nil
 }
 
-/*func (u *UserRepository) PostUsers(user models.User) models.User {
-}*/
+func (u *UserRepository) DeleteUsersId(ctx context.Context, id string) error {
+	res, err := u.collection().DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return fmt.Errorf("deleting user: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (u *UserRepository) GetUserCredentials(ctx context.Context, email string) (models.User, string, error) {
+	var doc userDocument
+	err := u.collection().FindOne(ctx, bson.D{{Key: "email", Value: strings.ToLower(email)}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.User{}, "", fmt.Errorf("user %s: %w", email, domain.ErrNotFound)
+	}
+	if err != nil {
+		return models.User{}, "", fmt.Errorf("finding user: %w", err)
+	}
+
+	return doc.toModel(), doc.PasswordHash, nil
+}
+
+func (u *UserRepository) UpdatePasswordHash(ctx context.Context, id string, hash string) error {
+	res, err := u.collection().UpdateByID(ctx, id, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "password_hash", Value: hash},
+	}}})
+	if err != nil {
+		return fmt.Errorf("updating password hash: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (u *UserRepository) collection() *mongo.Collection {
+	return u.db.Collection(usersCollection)
+}
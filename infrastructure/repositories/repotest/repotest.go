@@ -0,0 +1,1215 @@
+// Package repotest is a conformance suite for implementations of the domain
+// repositories. Every implementation is expected to pass it unchanged.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bersennaidoo/agentco/domain"
+	"github.com/bersennaidoo/agentco/domain/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Factory builds empty repositories for a single test.
+type Factory struct {
+	Users func(t *testing.T) domain.UserRepository
+	Jobs  func(t *testing.T) domain.JobRepository
+	// JobApplications returns a job application repository together with
+	// the job repository holding the jobs it applies to.
+	JobApplications func(t *testing.T) (domain.JobRepository, domain.JobApplicationRepository)
+	Sessions        func(t *testing.T) domain.SessionRepository
+	JobSeries       func(t *testing.T) domain.JobSeriesRepository
+	Availability    func(t *testing.T) domain.AvailabilityRepository
+	CalendarFeeds   func(t *testing.T) domain.CalendarFeedRepository
+	Pets            func(t *testing.T) domain.PetRepository
+}
+
+// Run runs the whole suite against the repositories built by f.
+func Run(t *testing.T, f Factory) {
+	t.Run("UserRepository", func(t *testing.T) { testUserRepository(t, f.Users) })
+	t.Run("JobRepository", func(t *testing.T) { testJobRepository(t, f.Jobs) })
+	t.Run("JobApplicationRepository", func(t *testing.T) { testJobApplicationRepository(t, f.JobApplications) })
+	t.Run("SessionRepository", func(t *testing.T) { testSessionRepository(t, f.Sessions) })
+	t.Run("JobSeriesRepository", func(t *testing.T) { testJobSeriesRepository(t, f.JobSeries) })
+	t.Run("AvailabilityRepository", func(t *testing.T) { testAvailabilityRepository(t, f.Availability) })
+	t.Run("CalendarFeedRepository", func(t *testing.T) { testCalendarFeedRepository(t, f.CalendarFeeds) })
+	t.Run("PetRepository", func(t *testing.T) { testPetRepository(t, f.Pets) })
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func newUser(email string, roles ...models.UserRoles) models.User {
+	return models.User{
+		Email:    openapi_types.Email(email),
+		FullName: "Jane Doe",
+		Roles:    roles,
+	}
+}
+
+func newJob(creator string, startsAt time.Time) models.Job {
+	return models.Job{
+		CreatorUserId: ptr(creator),
+		Description:   "Walk Rex around the park",
+		Dog:           &models.JobDog{Name: ptr("Rex"), Breed: "Beagle", Size: models.Small, YearsOld: 3},
+		Activities:    []models.JobActivities{models.Walk},
+		StartsAt:      startsAt,
+		EndsAt:        startsAt.Add(time.Hour),
+	}
+}
+
+func expectErr(t *testing.T, err, target error) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Fatalf("expected error %v, got %v", target, err)
+	}
+}
+
+func expectNoErr(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func testUserRepository(t *testing.T, newRepo func(t *testing.T) domain.UserRepository) {
+	ctx := context.Background()
+
+	t.Run("post and get", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostUsers(ctx, newUser("Jane@Example.com", models.PetOwner), "hash")
+		expectNoErr(t, err)
+		if created.Id == nil || *created.Id == "" {
+			t.Fatal("expected an id to be assigned")
+		}
+		if created.CreatedAt == nil || created.UpdatedAt == nil {
+			t.Fatal("expected timestamps to be set")
+		}
+		if created.Password != nil {
+			t.Fatal("password must never be returned")
+		}
+		if created.Email != "jane@example.com" {
+			t.Fatalf("expected email to be normalised, got %s", created.Email)
+		}
+
+		got, err := repo.GetUsersId(ctx, *created.Id)
+		expectNoErr(t, err)
+		if got.FullName != created.FullName || len(got.Roles) != 1 || got.Roles[0] != models.PetOwner {
+			t.Fatalf("unexpected user %+v", got)
+		}
+	})
+
+	t.Run("duplicate email", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.PostUsers(ctx, newUser("jane@example.com", models.PetOwner), "hash")
+		expectNoErr(t, err)
+		_, err = repo.PostUsers(ctx, newUser("JANE@example.com", models.PetSitter), "hash")
+		expectErr(t, err, domain.ErrConflict)
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostUsers(ctx, newUser("jane@example.com", models.PetOwner), "hash")
+		expectNoErr(t, err)
+
+		user, hash, err := repo.GetUserCredentials(ctx, "JANE@example.com")
+		expectNoErr(t, err)
+		if *user.Id != *created.Id || hash != "hash" {
+			t.Fatalf("unexpected credentials %+v %q", user, hash)
+		}
+
+		expectNoErr(t, repo.UpdatePasswordHash(ctx, *created.Id, "rehashed"))
+		_, hash, err = repo.GetUserCredentials(ctx, "jane@example.com")
+		expectNoErr(t, err)
+		if hash != "rehashed" {
+			t.Fatalf("expected rehashed password, got %q", hash)
+		}
+
+		_, _, err = repo.GetUserCredentials(ctx, "nobody@example.com")
+		expectErr(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("put keeps password when hash is empty", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostUsers(ctx, newUser("jane@example.com", models.PetOwner), "hash")
+		expectNoErr(t, err)
+
+		update := newUser("jane.doe@example.com", models.PetOwner, models.PetSitter)
+		update.FullName = "Jane Q. Doe"
+		updated, err := repo.PutUsersId(ctx, *created.Id, update, "")
+		expectNoErr(t, err)
+		if updated.FullName != "Jane Q. Doe" || len(updated.Roles) != 2 {
+			t.Fatalf("unexpected user %+v", updated)
+		}
+		if !updated.CreatedAt.Equal(*created.CreatedAt) {
+			t.Fatal("created_at must not change on update")
+		}
+
+		_, hash, err := repo.GetUserCredentials(ctx, "jane.doe@example.com")
+		expectNoErr(t, err)
+		if hash != "hash" {
+			t.Fatalf("expected password to be kept, got %q", hash)
+		}
+	})
+
+	t.Run("put conflicting email", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.PostUsers(ctx, newUser("jane@example.com", models.PetOwner), "hash")
+		expectNoErr(t, err)
+		other, err := repo.PostUsers(ctx, newUser("john@example.com", models.PetOwner), "hash")
+		expectNoErr(t, err)
+
+		_, err = repo.PutUsersId(ctx, *other.Id, newUser("jane@example.com", models.PetOwner), "")
+		expectErr(t, err, domain.ErrConflict)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostUsers(ctx, newUser("jane@example.com", models.PetOwner), "hash")
+		expectNoErr(t, err)
+
+		expectNoErr(t, repo.DeleteUsersId(ctx, *created.Id))
+		_, err = repo.GetUsersId(ctx, *created.Id)
+		expectErr(t, err, domain.ErrNotFound)
+		expectErr(t, repo.DeleteUsersId(ctx, *created.Id), domain.ErrNotFound)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetUsersId(ctx, "missing")
+		expectErr(t, err, domain.ErrNotFound)
+		_, err = repo.PutUsersId(ctx, "missing", newUser("jane@example.com", models.PetOwner), "")
+		expectErr(t, err, domain.ErrNotFound)
+		expectErr(t, repo.UpdatePasswordHash(ctx, "missing", "hash"), domain.ErrNotFound)
+	})
+}
+
+func testJobRepository(t *testing.T, newRepo func(t *testing.T) domain.JobRepository) {
+	ctx := context.Background()
+	startsAt := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("post and get", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		if created.Id == nil || *created.Id == "" {
+			t.Fatal("expected an id to be assigned")
+		}
+		if created.WorkerUserId != nil {
+			t.Fatal("a new job must be open")
+		}
+
+		got, err := repo.GetJobsId(ctx, *created.Id)
+		expectNoErr(t, err)
+		if *got.CreatorUserId != "owner" || !got.StartsAt.Equal(startsAt) || !got.EndsAt.Equal(startsAt.Add(time.Hour)) {
+			t.Fatalf("unexpected job %+v", got)
+		}
+		if got.Dog == nil || got.Dog.Breed != "Beagle" || *got.Dog.Name != "Rex" || got.Dog.Size != models.Small {
+			t.Fatalf("unexpected dog %+v", got.Dog)
+		}
+		if len(got.Activities) != 1 || got.Activities[0] != models.Walk {
+			t.Fatalf("unexpected activities %v", got.Activities)
+		}
+	})
+
+	t.Run("put leaves read-only fields", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+
+		update := newJob("intruder", startsAt.Add(24*time.Hour))
+		update.WorkerUserId = ptr("sitter")
+		update.Description = "Drop in and feed Rex"
+		update.Activities = []models.JobActivities{models.Dropin}
+		updated, err := repo.PutJobsId(ctx, *created.Id, update, 0)
+		expectNoErr(t, err)
+
+		if *updated.CreatorUserId != "owner" || updated.WorkerUserId != nil {
+			t.Fatalf("read-only fields changed: %+v", updated)
+		}
+		if updated.Description != "Drop in and feed Rex" || !updated.StartsAt.Equal(startsAt.Add(24*time.Hour)) {
+			t.Fatalf("editable fields not updated: %+v", updated)
+		}
+	})
+
+	t.Run("pets", func(t *testing.T) {
+		repo := newRepo(t)
+
+		job := newJob("owner", startsAt)
+		job.PetIds = &[]string{"p1", "p2"}
+		job.Dogs = &[]models.JobDog{
+			*job.Dog,
+			{Name: ptr("Bella"), Breed: "Poodle", Size: models.Medium, YearsOld: 7},
+		}
+		created, err := repo.PostJobs(ctx, job)
+		expectNoErr(t, err)
+
+		got, err := repo.GetJobsId(ctx, *created.Id)
+		expectNoErr(t, err)
+		if got.PetIds == nil || len(*got.PetIds) != 2 || (*got.PetIds)[1] != "p2" {
+			t.Fatalf("unexpected pet ids %v", got.PetIds)
+		}
+		if got.Dogs == nil || len(*got.Dogs) != 2 || *(*got.Dogs)[1].Name != "Bella" || (*got.Dogs)[1].YearsOld != 7 {
+			t.Fatalf("unexpected dogs %+v", got.Dogs)
+		}
+
+		jobs, total, err := repo.GetJobs(ctx, domain.JobQuery{DogSize: ptr(models.Medium)})
+		expectNoErr(t, err)
+		if total != 1 || *jobs[0].Id != *created.Id {
+			t.Fatalf("job not found by the size of its second dog: %d %v", total, jobs)
+		}
+
+		updated, err := repo.PutJobsId(ctx, *created.Id, newJob("owner", startsAt), 0)
+		expectNoErr(t, err)
+		if updated.PetIds != nil || updated.Dogs == nil || len(*updated.Dogs) != 1 || *(*updated.Dogs)[0].Name != "Rex" {
+			t.Fatalf("pets not replaced: %v %+v", updated.PetIds, updated.Dogs)
+		}
+
+		_, total, err = repo.GetJobs(ctx, domain.JobQuery{DogSize: ptr(models.Medium)})
+		expectNoErr(t, err)
+		if total != 0 {
+			t.Fatalf("job still found by the size of a replaced dog")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+
+		expectNoErr(t, repo.DeleteJobsId(ctx, *created.Id))
+		_, err = repo.GetJobsId(ctx, *created.Id)
+		expectErr(t, err, domain.ErrNotFound)
+		expectErr(t, repo.DeleteJobsId(ctx, *created.Id), domain.ErrNotFound)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.PutJobsId(ctx, "missing", newJob("owner", startsAt), 0)
+		expectErr(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("list avoiding intervals", func(t *testing.T) {
+		repo := newRepo(t)
+
+		early, err := repo.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		_, err = repo.PostJobs(ctx, newJob("owner", startsAt.Add(2*time.Hour)))
+		expectNoErr(t, err)
+		late, err := repo.PostJobs(ctx, newJob("owner", startsAt.Add(4*time.Hour)))
+		expectNoErr(t, err)
+
+		busy := domain.Interval{Start: startsAt.Add(90 * time.Minute), End: startsAt.Add(4 * time.Hour)}
+		found, total, err := repo.GetJobs(ctx, domain.JobQuery{Avoid: []domain.Interval{busy}})
+		expectNoErr(t, err)
+		if total != 2 || len(found) != 2 || *found[0].Id != *early.Id || *found[1].Id != *late.Id {
+			t.Fatalf("expected the early and late jobs, got %d of %d", len(found), total)
+		}
+	})
+
+	t.Run("occurrences", func(t *testing.T) {
+		repo := newRepo(t)
+
+		occurrence := func(recurrenceId time.Time) models.Job {
+			job := newJob("owner", recurrenceId)
+			job.SeriesId = ptr("series")
+			job.RecurrenceId = ptr(recurrenceId)
+			return job
+		}
+		first, err := repo.PostJobs(ctx, occurrence(startsAt))
+		expectNoErr(t, err)
+		second, err := repo.PostJobs(ctx, occurrence(startsAt.Add(24*time.Hour)))
+		expectNoErr(t, err)
+		_, err = repo.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+
+		_, err = repo.PostJobs(ctx, occurrence(startsAt))
+		expectErr(t, err, domain.ErrConflict)
+
+		if *first.SeriesId != "series" || !first.RecurrenceId.Equal(startsAt) || *first.Detached {
+			t.Fatalf("unexpected occurrence %+v", first)
+		}
+
+		found, total, err := repo.GetJobs(ctx, domain.JobQuery{SeriesId: ptr("series")})
+		expectNoErr(t, err)
+		if total != 2 || len(found) != 2 {
+			t.Fatalf("expected both occurrences, got %d of %d", len(found), total)
+		}
+		found, total, err = repo.GetJobs(ctx, domain.JobQuery{SeriesId: ptr("series"), RecurringAfter: ptr(startsAt.Add(time.Hour))})
+		expectNoErr(t, err)
+		if total != 1 || *found[0].Id != *second.Id {
+			t.Fatalf("expected the second occurrence, got %d of %d", len(found), total)
+		}
+
+		update := occurrence(startsAt.Add(2 * time.Hour))
+		update.Description = "Drop in and feed Rex"
+		updated, err := repo.UpdateOccurrence(ctx, *first.Id, update, 0)
+		expectNoErr(t, err)
+		if updated.Description != "Drop in and feed Rex" || !updated.StartsAt.Equal(startsAt.Add(2*time.Hour)) || *updated.Detached {
+			t.Fatalf("occurrence not updated: %+v", updated)
+		}
+		if !updated.RecurrenceId.Equal(startsAt) {
+			t.Fatalf("recurrence id changed: %v", updated.RecurrenceId)
+		}
+
+		detached, err := repo.PutJobsId(ctx, *first.Id, update, 0)
+		expectNoErr(t, err)
+		if !*detached.Detached || *detached.SeriesId != "series" {
+			t.Fatalf("expected the occurrence to be detached: %+v", detached)
+		}
+		_, err = repo.UpdateOccurrence(ctx, *first.Id, update, 0)
+		expectErr(t, err, domain.ErrConflict)
+		_, err = repo.UpdateOccurrence(ctx, "missing", update, 0)
+		expectErr(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		repo := newRepo(t)
+
+		walk := newJob("alice", startsAt)
+		daycare := newJob("alice", startsAt.Add(48*time.Hour))
+		daycare.Activities = []models.JobActivities{models.Daycare, models.Walk}
+		daycare.Dog.Size = models.Large
+		boarding := newJob("bob", startsAt.Add(24*time.Hour))
+		boarding.Activities = []models.JobActivities{models.Boarding}
+		boarding.Dog = nil
+
+		var ids []string
+		for _, job := range []models.Job{walk, daycare, boarding} {
+			created, err := repo.PostJobs(ctx, job)
+			expectNoErr(t, err)
+			ids = append(ids, *created.Id)
+		}
+
+		expectIds := func(t *testing.T, query domain.JobQuery, wantTotal int, want ...string) {
+			t.Helper()
+
+			jobs, total, err := repo.GetJobs(ctx, query)
+			expectNoErr(t, err)
+			if total != wantTotal {
+				t.Fatalf("expected total %d, got %d", wantTotal, total)
+			}
+			if len(jobs) != len(want) {
+				t.Fatalf("expected %d jobs, got %d", len(want), len(jobs))
+			}
+			for i, job := range jobs {
+				if *job.Id != want[i] {
+					t.Fatalf("job %d: expected %s, got %s", i, want[i], *job.Id)
+				}
+			}
+		}
+
+		expectIds(t, domain.JobQuery{}, 3, ids[0], ids[2], ids[1])
+		expectIds(t, domain.JobQuery{Descending: true}, 3, ids[1], ids[2], ids[0])
+		expectIds(t, domain.JobQuery{Limit: 1, Offset: 1}, 3, ids[2])
+		expectIds(t, domain.JobQuery{Offset: 5}, 3)
+		expectIds(t, domain.JobQuery{Activities: []models.JobActivities{models.Walk}}, 2, ids[0], ids[1])
+		expectIds(t, domain.JobQuery{Activities: []models.JobActivities{models.Walk, models.Daycare}}, 1, ids[1])
+		expectIds(t, domain.JobQuery{DogSize: ptr(models.Large)}, 1, ids[1])
+		expectIds(t, domain.JobQuery{CreatorUserId: ptr("bob")}, 1, ids[2])
+		expectIds(t, domain.JobQuery{Open: ptr(true)}, 3, ids[0], ids[2], ids[1])
+		expectIds(t, domain.JobQuery{Open: ptr(false)}, 0)
+		expectIds(t, domain.JobQuery{
+			StartsAfter: ptr(startsAt.Add(time.Hour)),
+			EndsBefore:  ptr(startsAt.Add(25 * time.Hour)),
+		}, 1, ids[2])
+		expectIds(t, domain.JobQuery{
+			EndsAfter:    ptr(startsAt.Add(time.Hour)),
+			StartsBefore: ptr(startsAt.Add(48 * time.Hour)),
+		}, 1, ids[2])
+		expectIds(t, domain.JobQuery{
+			EndsAfter:    ptr(startsAt.Add(30 * time.Minute)),
+			StartsBefore: ptr(startsAt.Add(24*time.Hour + 30*time.Minute)),
+			StartsAfter:  ptr(startsAt.Add(time.Hour)),
+		}, 1, ids[2])
+	})
+}
+
+func testJobApplicationRepository(t *testing.T, newRepos func(t *testing.T) (domain.JobRepository, domain.JobApplicationRepository)) {
+	ctx := context.Background()
+	startsAt := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	apply := func(t *testing.T, repo domain.JobApplicationRepository, jobId, userId string) models.JobApplication {
+		t.Helper()
+
+		application, err := repo.CreateJobApplication(ctx, models.JobApplication{JobId: ptr(jobId), UserId: ptr(userId)})
+		expectNoErr(t, err)
+		return application
+	}
+
+	postJob := func(t *testing.T, jobs domain.JobRepository) string {
+		t.Helper()
+
+		job, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		return *job.Id
+	}
+
+	expectStatus := func(t *testing.T, repo domain.JobApplicationRepository, id string, want models.JobApplicationStatus) {
+		t.Helper()
+
+		got, err := repo.GetJobApplication(ctx, id)
+		expectNoErr(t, err)
+		if *got.Status != want {
+			t.Fatalf("expected application %s to be %s, got %s", id, want, *got.Status)
+		}
+	}
+
+	t.Run("create defaults to applying", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job := postJob(t, jobs)
+		created, err := repo.CreateJobApplication(ctx, models.JobApplication{
+			JobId:  ptr(job),
+			UserId: ptr("sitter"),
+			Status: ptr(models.ACCEPTED),
+		})
+		expectNoErr(t, err)
+		if created.Id == nil || *created.Status != models.APPLYING {
+			t.Fatalf("unexpected application %+v", created)
+		}
+
+		got, err := repo.GetJobApplication(ctx, *created.Id)
+		expectNoErr(t, err)
+		if *got.JobId != job || *got.UserId != "sitter" {
+			t.Fatalf("unexpected application %+v", got)
+		}
+	})
+
+	t.Run("duplicate application", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job := postJob(t, jobs)
+		apply(t, repo, job, "sitter")
+		_, err := repo.CreateJobApplication(ctx, models.JobApplication{JobId: ptr(job), UserId: ptr("sitter")})
+		expectErr(t, err, domain.ErrConflict)
+		apply(t, repo, postJob(t, jobs), "sitter")
+	})
+
+	t.Run("list by job", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job := postJob(t, jobs)
+		first := apply(t, repo, job, "a")
+		second := apply(t, repo, job, "b")
+		apply(t, repo, postJob(t, jobs), "c")
+
+		list, err := repo.GetApplicationsByJobId(ctx, job)
+		expectNoErr(t, err)
+		if len(list) != 2 {
+			t.Fatalf("expected 2 applications, got %d", len(list))
+		}
+		ids := map[string]bool{*list[0].Id: true, *list[1].Id: true}
+		if !ids[*first.Id] || !ids[*second.Id] {
+			t.Fatalf("unexpected applications %+v", list)
+		}
+
+		empty, err := repo.GetApplicationsByJobId(ctx, "none")
+		expectNoErr(t, err)
+		if empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty, non-nil list, got %#v", empty)
+		}
+	})
+
+	t.Run("accept fills the job and denies the others", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		chosen := apply(t, repo, *job.Id, "a")
+		other := apply(t, repo, *job.Id, "b")
+		denied := apply(t, repo, *job.Id, "c")
+		_, err = repo.DenyJobApplication(ctx, *denied.Id)
+		expectNoErr(t, err)
+
+		accepted, err := repo.AcceptJobApplication(ctx, *chosen.Id, 0)
+		expectNoErr(t, err)
+		if *accepted.Status != models.ACCEPTED {
+			t.Fatalf("unexpected status %s", *accepted.Status)
+		}
+		expectStatus(t, repo, *other.Id, models.DENIED)
+		expectStatus(t, repo, *denied.Id, models.DENIED)
+
+		filled, err := jobs.GetJobsId(ctx, *job.Id)
+		expectNoErr(t, err)
+		if filled.WorkerUserId == nil || *filled.WorkerUserId != "a" {
+			t.Fatalf("expected worker a, got %v", filled.WorkerUserId)
+		}
+
+		_, total, err := jobs.GetJobs(ctx, domain.JobQuery{Open: ptr(false)})
+		expectNoErr(t, err)
+		if total != 1 {
+			t.Fatalf("expected the job to be listed as filled, got %d", total)
+		}
+
+		_, err = repo.AcceptJobApplication(ctx, *other.Id, 0)
+		expectErr(t, err, domain.ErrConflict)
+		_, err = repo.DenyJobApplication(ctx, *chosen.Id)
+		expectErr(t, err, domain.ErrConflict)
+	})
+
+	t.Run("list by user", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		accepted := apply(t, repo, *job.Id, "sitter")
+		_, err = repo.AcceptJobApplication(ctx, *accepted.Id, 0)
+		expectNoErr(t, err)
+		apply(t, repo, postJob(t, jobs), "sitter")
+		third := postJob(t, jobs)
+		apply(t, repo, third, "sitter")
+		apply(t, repo, third, "someone else")
+
+		all, total, err := repo.GetJobApplications(ctx, domain.JobApplicationQuery{UserId: "sitter"})
+		expectNoErr(t, err)
+		if total != 3 || len(all) != 3 {
+			t.Fatalf("expected 3 applications, got %d of %d", len(all), total)
+		}
+
+		seen := map[string]bool{}
+		for offset := 0; offset < 3; offset += 2 {
+			page, total, err := repo.GetJobApplications(ctx, domain.JobApplicationQuery{UserId: "sitter", Limit: 2, Offset: offset})
+			expectNoErr(t, err)
+			if total != 3 {
+				t.Fatalf("expected a total of 3, got %d", total)
+			}
+			for _, application := range page {
+				seen[*application.Id] = true
+			}
+		}
+		if len(seen) != 3 {
+			t.Fatalf("expected paging to cover 3 applications, got %d", len(seen))
+		}
+
+		pending, total, err := repo.GetJobApplications(ctx, domain.JobApplicationQuery{UserId: "sitter", Status: ptr(models.APPLYING)})
+		expectNoErr(t, err)
+		if total != 2 || len(pending) != 2 {
+			t.Fatalf("expected 2 pending applications, got %d of %d", len(pending), total)
+		}
+
+		none, total, err := repo.GetJobApplications(ctx, domain.JobApplicationQuery{UserId: "nobody"})
+		expectNoErr(t, err)
+		if total != 0 || none == nil || len(none) != 0 {
+			t.Fatalf("expected an empty, non-nil list, got %#v", none)
+		}
+	})
+
+	t.Run("list jobs by worker and participant", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		worked, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		application := apply(t, repo, *worked.Id, "both")
+		_, err = repo.AcceptJobApplication(ctx, *application.Id, 0)
+		expectNoErr(t, err)
+		_, err = jobs.PostJobs(ctx, newJob("both", startsAt.Add(time.Hour)))
+		expectNoErr(t, err)
+		_, err = jobs.PostJobs(ctx, newJob("owner", startsAt.Add(2*time.Hour)))
+		expectNoErr(t, err)
+
+		working, total, err := jobs.GetJobs(ctx, domain.JobQuery{WorkerUserId: ptr("both")})
+		expectNoErr(t, err)
+		if total != 1 || *working[0].Id != *worked.Id {
+			t.Fatalf("expected only the worked job, got %d", total)
+		}
+
+		working, total, err = jobs.GetJobs(ctx, domain.JobQuery{WorkerUserIds: []string{"other", "both"}})
+		expectNoErr(t, err)
+		if total != 1 || *working[0].Id != *worked.Id {
+			t.Fatalf("expected only the worked job for any of the workers, got %d", total)
+		}
+
+		_, total, err = jobs.GetJobs(ctx, domain.JobQuery{ParticipantUserId: ptr("both")})
+		expectNoErr(t, err)
+		if total != 2 {
+			t.Fatalf("expected 2 jobs to involve the user, got %d", total)
+		}
+
+		_, total, err = jobs.GetJobs(ctx, domain.JobQuery{ParticipantUserId: ptr("both"), Open: ptr(true)})
+		expectNoErr(t, err)
+		if total != 1 {
+			t.Fatalf("expected 1 open job to involve the user, got %d", total)
+		}
+	})
+
+	t.Run("accept on a filled job", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		first := apply(t, repo, *job.Id, "a")
+		_, err = repo.AcceptJobApplication(ctx, *first.Id, 0)
+		expectNoErr(t, err)
+
+		late := apply(t, repo, *job.Id, "b")
+		_, err = repo.AcceptJobApplication(ctx, *late.Id, 0)
+		expectErr(t, err, domain.ErrConflict)
+		expectStatus(t, repo, *late.Id, models.APPLYING)
+	})
+
+	t.Run("accept on an overlapping job", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		first, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		overlapping, err := jobs.PostJobs(ctx, newJob("owner", startsAt.Add(30*time.Minute)))
+		expectNoErr(t, err)
+		near, err := jobs.PostJobs(ctx, newJob("owner", startsAt.Add(90*time.Minute)))
+		expectNoErr(t, err)
+
+		_, err = repo.AcceptJobApplication(ctx, *apply(t, repo, *first.Id, "sitter").Id, 0)
+		expectNoErr(t, err)
+
+		application := apply(t, repo, *overlapping.Id, "sitter")
+		_, err = repo.AcceptJobApplication(ctx, *application.Id, 0)
+		var conflict *domain.ScheduleConflictError
+		if !errors.As(err, &conflict) || conflict.JobId != *first.Id {
+			t.Fatalf("expected a schedule conflict with %s, got %v", *first.Id, err)
+		}
+		expectErr(t, err, domain.ErrConflict)
+		expectStatus(t, repo, *application.Id, models.APPLYING)
+
+		unfilled, err := jobs.GetJobsId(ctx, *overlapping.Id)
+		expectNoErr(t, err)
+		if unfilled.WorkerUserId != nil {
+			t.Fatalf("expected the overlapping job to stay open, got worker %s", *unfilled.WorkerUserId)
+		}
+
+		application = apply(t, repo, *near.Id, "sitter")
+		_, err = repo.AcceptJobApplication(ctx, *application.Id, time.Hour)
+		expectErr(t, err, domain.ErrConflict)
+		_, err = repo.AcceptJobApplication(ctx, *application.Id, 30*time.Minute)
+		expectNoErr(t, err)
+	})
+
+	t.Run("moving a worked job", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		first, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		later, err := jobs.PostJobs(ctx, newJob("owner", startsAt.Add(3*time.Hour)))
+		expectNoErr(t, err)
+		for _, job := range []models.Job{first, later} {
+			_, err = repo.AcceptJobApplication(ctx, *apply(t, repo, *job.Id, "sitter").Id, 0)
+			expectNoErr(t, err)
+		}
+
+		_, err = jobs.PutJobsId(ctx, *later.Id, newJob("owner", startsAt.Add(30*time.Minute)), 0)
+		var conflict *domain.ScheduleConflictError
+		if !errors.As(err, &conflict) || conflict.JobId != *first.Id {
+			t.Fatalf("expected a schedule conflict with %s, got %v", *first.Id, err)
+		}
+		unmoved, err := jobs.GetJobsId(ctx, *later.Id)
+		expectNoErr(t, err)
+		if !unmoved.StartsAt.Equal(startsAt.Add(3 * time.Hour)) {
+			t.Fatalf("expected the job to stay put, got %s", unmoved.StartsAt)
+		}
+
+		_, err = jobs.PutJobsId(ctx, *later.Id, newJob("owner", startsAt.Add(90*time.Minute)), time.Hour)
+		expectErr(t, err, domain.ErrConflict)
+		_, err = jobs.PutJobsId(ctx, *later.Id, newJob("owner", startsAt.Add(90*time.Minute)), 30*time.Minute)
+		expectNoErr(t, err)
+
+		edit := newJob("owner", startsAt)
+		edit.Description = "Walk Rex twice"
+		_, err = jobs.PutJobsId(ctx, *first.Id, edit, time.Hour)
+		expectNoErr(t, err)
+	})
+
+	t.Run("withdrawing an accepted application reopens the job", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		application := apply(t, repo, *job.Id, "a")
+		_, err = repo.AcceptJobApplication(ctx, *application.Id, 0)
+		expectNoErr(t, err)
+
+		expectNoErr(t, repo.WithdrawJobApplication(ctx, *application.Id))
+		_, err = repo.GetJobApplication(ctx, *application.Id)
+		expectErr(t, err, domain.ErrNotFound)
+
+		reopened, err := jobs.GetJobsId(ctx, *job.Id)
+		expectNoErr(t, err)
+		if reopened.WorkerUserId != nil {
+			t.Fatalf("expected the job to be open, got worker %s", *reopened.WorkerUserId)
+		}
+
+		expectErr(t, repo.WithdrawJobApplication(ctx, *application.Id), domain.ErrNotFound)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, repo := newRepos(t)
+
+		_, err := repo.GetJobApplication(ctx, "missing")
+		expectErr(t, err, domain.ErrNotFound)
+		_, err = repo.AcceptJobApplication(ctx, "missing", 0)
+		expectErr(t, err, domain.ErrNotFound)
+		_, err = repo.DenyJobApplication(ctx, "missing")
+		expectErr(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("delete job", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		other, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		apply(t, repo, *job.Id, "a")
+		kept := apply(t, repo, *other.Id, "a")
+
+		expectNoErr(t, repo.DeleteJob(ctx, *job.Id, nil))
+		_, err = jobs.GetJobsId(ctx, *job.Id)
+		expectErr(t, err, domain.ErrNotFound)
+		list, err := repo.GetApplicationsByJobId(ctx, *job.Id)
+		expectNoErr(t, err)
+		if len(list) != 0 {
+			t.Fatalf("expected applications to be deleted, got %d", len(list))
+		}
+		_, err = repo.GetJobApplication(ctx, *kept.Id)
+		expectNoErr(t, err)
+
+		expectErr(t, repo.DeleteJob(ctx, *job.Id, nil), domain.ErrNotFound)
+		_, err = repo.CreateJobApplication(ctx, models.JobApplication{JobId: job.Id, UserId: ptr("b")})
+		expectErr(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("delete job after accept", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job, err := jobs.PostJobs(ctx, newJob("owner", startsAt))
+		expectNoErr(t, err)
+		application := apply(t, repo, *job.Id, "a")
+		_, err = repo.AcceptJobApplication(ctx, *application.Id, 0)
+		expectNoErr(t, err)
+
+		ran := false
+		before := func(ctx context.Context) error {
+			ran = true
+			return nil
+		}
+		expectErr(t, repo.DeleteJob(ctx, *job.Id, before), domain.ErrConflict)
+		if ran {
+			t.Fatalf("expected before not to run for a job that is kept")
+		}
+		expectErr(t, jobs.DeleteJobsId(ctx, *job.Id), domain.ErrConflict)
+		_, err = jobs.GetJobsId(ctx, *job.Id)
+		expectNoErr(t, err)
+		_, err = repo.GetJobApplication(ctx, *application.Id)
+		expectNoErr(t, err)
+	})
+	t.Run("delete job fails with before", func(t *testing.T) {
+		jobs, repo := newRepos(t)
+
+		job := postJob(t, jobs)
+		application := apply(t, repo, job, "a")
+
+		failed := errors.New("failed")
+		err := repo.DeleteJob(ctx, job, func(ctx context.Context) error { return failed })
+		if !errors.Is(err, failed) {
+			t.Fatalf("expected %v, got %v", failed, err)
+		}
+		_, err = jobs.GetJobsId(ctx, job)
+		expectNoErr(t, err)
+		_, err = repo.GetJobApplication(ctx, *application.Id)
+		expectNoErr(t, err)
+	})
+}
+
+func testSessionRepository(t *testing.T, newRepo func(t *testing.T) domain.SessionRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("create, get and delete for user", func(t *testing.T) {
+		repo := newRepo(t)
+
+		mine := domain.Session{Id: "s1", UserId: "u1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		theirs := domain.Session{Id: "s2", UserId: "u2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		expectNoErr(t, repo.CreateSession(ctx, mine))
+		expectNoErr(t, repo.CreateSession(ctx, theirs))
+
+		got, err := repo.GetSession(ctx, "s1")
+		expectNoErr(t, err)
+		if got.UserId != "u1" || !got.ExpiresAt.Equal(mine.ExpiresAt) {
+			t.Fatalf("unexpected session %+v", got)
+		}
+
+		expectNoErr(t, repo.DeleteUserSessions(ctx, "u1"))
+		_, err = repo.GetSession(ctx, "s1")
+		expectErr(t, err, domain.ErrNotFound)
+		_, err = repo.GetSession(ctx, "s2")
+		expectNoErr(t, err)
+	})
+}
+
+func testJobSeriesRepository(t *testing.T, newRepo func(t *testing.T) domain.JobSeriesRepository) {
+	ctx := context.Background()
+	startsAt := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	newSeries := func(creator string) models.JobSeries {
+		return models.JobSeries{
+			CreatorUserId: ptr(creator),
+			Rrule:         "FREQ=WEEKLY;BYDAY=MO,WE",
+			Timezone:      "Europe/Amsterdam",
+			StartsAt:      startsAt,
+			EndsAt:        startsAt.Add(time.Hour),
+			Description:   "Walk Rex around the park",
+			Dog:           &models.JobDog{Name: ptr("Rex"), Breed: "Beagle", Size: models.Small, YearsOld: 3},
+			Activities:    []models.JobActivities{models.Walk},
+		}
+	}
+	date := func(t time.Time) openapi_types.Date {
+		return openapi_types.Date{Time: t}
+	}
+
+	t.Run("post, get and put", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostJobSeries(ctx, newSeries("owner"))
+		expectNoErr(t, err)
+		if created.Id == nil || *created.Id == "" {
+			t.Fatal("expected an id to be assigned")
+		}
+		if created.MaterializedUntil != nil {
+			t.Fatalf("a new series must not be materialized, got %v", created.MaterializedUntil)
+		}
+
+		got, err := repo.GetJobSeries(ctx, *created.Id)
+		expectNoErr(t, err)
+		if *got.CreatorUserId != "owner" || got.Rrule != "FREQ=WEEKLY;BYDAY=MO,WE" || got.Timezone != "Europe/Amsterdam" || !got.StartsAt.Equal(startsAt) {
+			t.Fatalf("unexpected series %+v", got)
+		}
+
+		update := newSeries("intruder")
+		update.Rrule = "FREQ=DAILY"
+		update.Exceptions = &[]openapi_types.Date{date(startsAt)}
+		updated, err := repo.PutJobSeries(ctx, *created.Id, update)
+		expectNoErr(t, err)
+		if *updated.CreatorUserId != "owner" || updated.Rrule != "FREQ=DAILY" {
+			t.Fatalf("unexpected series %+v", updated)
+		}
+		if len(*updated.Exceptions) != 1 || (*updated.Exceptions)[0].String() != "2030-05-01" {
+			t.Fatalf("unexpected exceptions %v", *updated.Exceptions)
+		}
+	})
+
+	t.Run("exceptions and applicants are added once", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostJobSeries(ctx, newSeries("owner"))
+		expectNoErr(t, err)
+
+		for i := 0; i < 2; i++ {
+			expectNoErr(t, repo.AddJobSeriesException(ctx, *created.Id, date(startsAt)))
+			expectNoErr(t, repo.AddJobSeriesApplicant(ctx, *created.Id, "sitter"))
+		}
+
+		got, err := repo.GetJobSeries(ctx, *created.Id)
+		expectNoErr(t, err)
+		if len(*got.Exceptions) != 1 || (*got.Exceptions)[0].String() != "2030-05-01" {
+			t.Fatalf("unexpected exceptions %v", *got.Exceptions)
+		}
+		if len(*got.ApplicantUserIds) != 1 || (*got.ApplicantUserIds)[0] != "sitter" {
+			t.Fatalf("unexpected applicants %v", *got.ApplicantUserIds)
+		}
+	})
+
+	t.Run("materialized until never moves back", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostJobSeries(ctx, newSeries("owner"))
+		expectNoErr(t, err)
+		other, err := repo.PostJobSeries(ctx, newSeries("owner"))
+		expectNoErr(t, err)
+
+		expectNoErr(t, repo.SetJobSeriesMaterializedUntil(ctx, *created.Id, startsAt.Add(48*time.Hour)))
+		expectNoErr(t, repo.SetJobSeriesMaterializedUntil(ctx, *created.Id, startsAt))
+
+		got, err := repo.GetJobSeries(ctx, *created.Id)
+		expectNoErr(t, err)
+		if got.MaterializedUntil == nil || !got.MaterializedUntil.Equal(startsAt.Add(48*time.Hour)) {
+			t.Fatalf("unexpected materialized until %v", got.MaterializedUntil)
+		}
+
+		due, err := repo.GetJobSeriesDue(ctx, startsAt.Add(24*time.Hour))
+		expectNoErr(t, err)
+		if len(due) != 1 || *due[0].Id != *other.Id {
+			t.Fatalf("expected only the unmaterialized series to be due, got %d", len(due))
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetJobSeries(ctx, "missing")
+		expectErr(t, err, domain.ErrNotFound)
+		_, err = repo.PutJobSeries(ctx, "missing", newSeries("owner"))
+		expectErr(t, err, domain.ErrNotFound)
+		expectErr(t, repo.AddJobSeriesException(ctx, "missing", date(startsAt)), domain.ErrNotFound)
+		expectErr(t, repo.AddJobSeriesApplicant(ctx, "missing", "sitter"), domain.ErrNotFound)
+		expectErr(t, repo.SetJobSeriesMaterializedUntil(ctx, "missing", startsAt), domain.ErrNotFound)
+	})
+}
+
+func testAvailabilityRepository(t *testing.T, newRepo func(t *testing.T) domain.AvailabilityRepository) {
+	ctx := context.Background()
+	startsAt := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	newAvailability := func(activities ...models.JobActivities) models.Availability {
+		return models.Availability{
+			Timezone: "Europe/Amsterdam",
+			WeeklySlots: []models.AvailabilitySlot{
+				{Day: models.Monday, Start: "08:00", End: "17:30"},
+			},
+			Blackouts: &[]models.AvailabilityBlackout{
+				{StartsAt: startsAt, EndsAt: startsAt.Add(48 * time.Hour), Reason: ptr("Holiday")},
+			},
+			Activities: activities,
+			DogSizes:   &[]models.JobDogSize{models.Small},
+		}
+	}
+
+	t.Run("put, get and replace", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetAvailability(ctx, "sitter")
+		expectErr(t, err, domain.ErrNotFound)
+
+		put, err := repo.PutAvailability(ctx, "sitter", newAvailability(models.Walk))
+		expectNoErr(t, err)
+		if *put.UserId != "sitter" {
+			t.Fatalf("unexpected user id %s", *put.UserId)
+		}
+
+		got, err := repo.GetAvailability(ctx, "sitter")
+		expectNoErr(t, err)
+		if got.Timezone != "Europe/Amsterdam" || len(got.WeeklySlots) != 1 || got.WeeklySlots[0] != (models.AvailabilitySlot{Day: models.Monday, Start: "08:00", End: "17:30"}) {
+			t.Fatalf("unexpected availability %+v", got)
+		}
+		if len(*got.Blackouts) != 1 || !(*got.Blackouts)[0].StartsAt.Equal(startsAt) || *(*got.Blackouts)[0].Reason != "Holiday" {
+			t.Fatalf("unexpected blackouts %+v", *got.Blackouts)
+		}
+		if len(*got.DogSizes) != 1 || (*got.DogSizes)[0] != models.Small {
+			t.Fatalf("unexpected dog sizes %v", *got.DogSizes)
+		}
+
+		replacement := newAvailability(models.Boarding)
+		replacement.Blackouts = nil
+		_, err = repo.PutAvailability(ctx, "sitter", replacement)
+		expectNoErr(t, err)
+		got, err = repo.GetAvailability(ctx, "sitter")
+		expectNoErr(t, err)
+		if len(got.Activities) != 1 || got.Activities[0] != models.Boarding || len(*got.Blackouts) != 0 {
+			t.Fatalf("availability not replaced: %+v", got)
+		}
+	})
+
+	t.Run("list by activity", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.PutAvailability(ctx, "b", newAvailability(models.Walk, models.Dropin))
+		expectNoErr(t, err)
+		_, err = repo.PutAvailability(ctx, "a", newAvailability(models.Walk))
+		expectNoErr(t, err)
+		_, err = repo.PutAvailability(ctx, "c", newAvailability(models.Boarding))
+		expectNoErr(t, err)
+
+		found, err := repo.GetAvailabilities(ctx, []models.JobActivities{models.Dropin, models.Walk})
+		expectNoErr(t, err)
+		if len(found) != 2 || *found[0].UserId != "a" || *found[1].UserId != "b" {
+			t.Fatalf("expected a and b, got %d", len(found))
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.PutAvailability(ctx, "sitter", newAvailability(models.Walk))
+		expectNoErr(t, err)
+
+		expectNoErr(t, repo.DeleteAvailability(ctx, "sitter"))
+		_, err = repo.GetAvailability(ctx, "sitter")
+		expectErr(t, err, domain.ErrNotFound)
+		expectErr(t, repo.DeleteAvailability(ctx, "sitter"), domain.ErrNotFound)
+	})
+}
+
+func testCalendarFeedRepository(t *testing.T, newRepo func(t *testing.T) domain.CalendarFeedRepository) {
+	ctx := context.Background()
+	startsAt := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []domain.CalendarEvent{
+		{JobId: "j1", Sequence: 2, Fingerprint: "f1", Summary: "Walk: Rex", StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour)},
+		{JobId: "j2", Sequence: 1, Fingerprint: "f2", Summary: "Boarding: Rex", StartsAt: startsAt, EndsAt: startsAt.Add(24 * time.Hour), Cancelled: true},
+	}
+
+	t.Run("token, events and token replacement", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetCalendarFeedByToken(ctx, "hash1")
+		expectErr(t, err, domain.ErrNotFound)
+
+		expectNoErr(t, repo.PutCalendarToken(ctx, "user", "hash1"))
+		feed, err := repo.GetCalendarFeedByToken(ctx, "hash1")
+		expectNoErr(t, err)
+		if feed.UserId != "user" || feed.TokenHash != "hash1" || len(feed.Events) != 0 {
+			t.Fatalf("unexpected feed %+v", feed)
+		}
+
+		expectNoErr(t, repo.PutCalendarEvents(ctx, "user", feed.Version, events))
+		expectErr(t, repo.PutCalendarEvents(ctx, "user", feed.Version, nil), domain.ErrConflict)
+		expectNoErr(t, repo.PutCalendarToken(ctx, "user", "hash2"))
+
+		_, err = repo.GetCalendarFeedByToken(ctx, "hash1")
+		expectErr(t, err, domain.ErrNotFound)
+		feed, err = repo.GetCalendarFeedByToken(ctx, "hash2")
+		expectNoErr(t, err)
+		if len(feed.Events) != 2 || feed.Version != 1 {
+			t.Fatalf("expected the events to be kept at version 1, got %d at %d", len(feed.Events), feed.Version)
+		}
+		got := feed.Events[1]
+		if got.JobId != "j2" || got.Sequence != 1 || got.Fingerprint != "f2" || got.Summary != "Boarding: Rex" || !got.StartsAt.Equal(startsAt) || !got.EndsAt.Equal(startsAt.Add(24*time.Hour)) || !got.Cancelled {
+			t.Fatalf("unexpected event %+v", got)
+		}
+	})
+
+	t.Run("tokens are unique", func(t *testing.T) {
+		repo := newRepo(t)
+
+		expectNoErr(t, repo.PutCalendarToken(ctx, "a", "hash"))
+		expectErr(t, repo.PutCalendarToken(ctx, "b", "hash"), domain.ErrConflict)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		expectErr(t, repo.PutCalendarEvents(ctx, "user", 0, events), domain.ErrNotFound)
+		expectErr(t, repo.DeleteCalendarFeed(ctx, "user"), domain.ErrNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo := newRepo(t)
+
+		expectNoErr(t, repo.PutCalendarToken(ctx, "user", "hash"))
+		expectNoErr(t, repo.DeleteCalendarFeed(ctx, "user"))
+		_, err := repo.GetCalendarFeedByToken(ctx, "hash")
+		expectErr(t, err, domain.ErrNotFound)
+	})
+}
+
+func testPetRepository(t *testing.T, newRepo func(t *testing.T) domain.PetRepository) {
+	ctx := context.Background()
+	birthDate := openapi_types.Date{Time: time.Date(2019, 2, 28, 0, 0, 0, 0, time.UTC)}
+
+	newPet := func(owner, name string) models.Pet {
+		return models.Pet{
+			OwnerUserId:  ptr(owner),
+			Name:         name,
+			Breed:        "Beagle",
+			Size:         models.Small,
+			BirthDate:    birthDate,
+			MedicalNotes: ptr("Allergic to chicken"),
+			Temperament:  &[]models.PetTemperament{models.GoodWithDogs, models.Anxious},
+		}
+	}
+
+	t.Run("post, get and put", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostPet(ctx, newPet("owner", "Rex"))
+		expectNoErr(t, err)
+		if created.Id == nil || *created.Id == "" {
+			t.Fatal("expected an id to be assigned")
+		}
+
+		got, err := repo.GetPet(ctx, *created.Id)
+		expectNoErr(t, err)
+		if *got.OwnerUserId != "owner" || got.Name != "Rex" || got.Breed != "Beagle" || got.Size != models.Small || got.BirthDate != birthDate {
+			t.Fatalf("unexpected pet %+v", got)
+		}
+		if *got.MedicalNotes != "Allergic to chicken" || got.FeedingInstructions != nil {
+			t.Fatalf("unexpected notes %v %v", got.MedicalNotes, got.FeedingInstructions)
+		}
+		if len(*got.Temperament) != 2 || (*got.Temperament)[1] != models.Anxious {
+			t.Fatalf("unexpected temperament %v", *got.Temperament)
+		}
+
+		update := newPet("intruder", "Max")
+		update.MedicalNotes = nil
+		update.FeedingInstructions = ptr("Twice a day")
+		update.Temperament = nil
+		updated, err := repo.PutPet(ctx, *created.Id, update)
+		expectNoErr(t, err)
+		if *updated.OwnerUserId != "owner" {
+			t.Fatalf("owner changed: %s", *updated.OwnerUserId)
+		}
+		if updated.Name != "Max" || updated.MedicalNotes != nil || *updated.FeedingInstructions != "Twice a day" || len(*updated.Temperament) != 0 {
+			t.Fatalf("editable fields not updated: %+v", updated)
+		}
+	})
+
+	t.Run("list by owner", func(t *testing.T) {
+		repo := newRepo(t)
+
+		for _, pet := range []models.Pet{newPet("owner", "Rex"), newPet("other", "Bella"), newPet("owner", "Max")} {
+			_, err := repo.PostPet(ctx, pet)
+			expectNoErr(t, err)
+		}
+
+		pets, err := repo.GetPets(ctx, "owner")
+		expectNoErr(t, err)
+		if len(pets) != 2 || pets[0].Name != "Max" || pets[1].Name != "Rex" {
+			t.Fatalf("expected Max and Rex, got %d", len(pets))
+		}
+
+		expectNoErr(t, repo.DeletePetsByOwner(ctx, "owner"))
+		pets, err = repo.GetPets(ctx, "owner")
+		expectNoErr(t, err)
+		if len(pets) != 0 {
+			t.Fatalf("expected no pets, got %d", len(pets))
+		}
+		pets, err = repo.GetPets(ctx, "other")
+		expectNoErr(t, err)
+		if len(pets) != 1 {
+			t.Fatalf("expected the other owner's pet to be kept, got %d", len(pets))
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.PostPet(ctx, newPet("owner", "Rex"))
+		expectNoErr(t, err)
+
+		expectNoErr(t, repo.DeletePet(ctx, *created.Id))
+		_, err = repo.GetPet(ctx, *created.Id)
+		expectErr(t, err, domain.ErrNotFound)
+		expectErr(t, repo.DeletePet(ctx, *created.Id), domain.ErrNotFound)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.PutPet(ctx, "missing", newPet("owner", "Rex"))
+		expectErr(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,160 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writePair generates a certificate for host and moves it into place at
+// certFile and keyFile by rename, the way secrets are usually replaced.
+// It returns the DER bytes of the certificate.
+func writePair(t *testing.T, certFile, keyFile, host string) []byte {
+	t.Helper()
+
+	cert, err := SelfSigned([]string{host}, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	key, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replace(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key}))
+	replace(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}))
+	return cert.Certificate[0]
+}
+
+func replace(t *testing.T, path string, content []byte) {
+	t.Helper()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func current(t *testing.T, r *Reloader) []byte {
+	t.Helper()
+
+	cert, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return cert.Certificate[0]
+}
+
+func TestNewReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	if _, err := NewReloader(certFile, keyFile); err == nil {
+		t.Fatalf("expected an error for missing files")
+	}
+
+	der := writePair(t, certFile, keyFile, "localhost")
+	r, err := NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(current(t, r), der) {
+		t.Fatalf("expected the certificate on disk")
+	}
+}
+
+func TestReloaderWatch(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	der := writePair(t, certFile, keyFile, "first.localhost")
+
+	r, err := NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.Watch(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}()
+
+	// Each step changes the files on disk; the certificate served must then
+	// become, or stay, the expected one. Steps are written again until they
+	// take effect, since the watcher may not be running yet for the first.
+	steps := []struct {
+		name   string
+		change func() []byte
+		swaps  bool
+	}{
+		{
+			name:   "new pair",
+			change: func() []byte { return writePair(t, certFile, keyFile, "second.localhost") },
+			swaps:  true,
+		},
+		{
+			name: "broken certificate",
+			change: func() []byte {
+				replace(t, certFile, []byte("not a certificate"))
+				return nil
+			},
+		},
+		{
+			name: "certificate of another key",
+			change: func() []byte {
+				otherCert, otherKey := filepath.Join(dir, "other.crt"), filepath.Join(dir, "other.key")
+				writePair(t, otherCert, otherKey, "other.localhost")
+				content, err := os.ReadFile(otherCert)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				replace(t, certFile, content)
+				return nil
+			},
+		},
+		{
+			name:   "fixed pair",
+			change: func() []byte { return writePair(t, certFile, keyFile, "third.localhost") },
+			swaps:  true,
+		},
+	}
+
+	for _, step := range steps {
+		if !step.swaps {
+			step.change()
+			time.Sleep(3 * reloadDelay)
+			if !bytes.Equal(current(t, r), der) {
+				t.Fatalf("%s: expected the previous certificate to stay in use", step.name)
+			}
+			continue
+		}
+
+		expected := step.change()
+		written := time.Now()
+		deadline := written.Add(10 * time.Second)
+		for !bytes.Equal(current(t, r), expected) {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: expected the certificate to be reloaded", step.name)
+			}
+			if time.Since(written) > 4*reloadDelay {
+				expected = step.change()
+				written = time.Now()
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		der = expected
+	}
+}
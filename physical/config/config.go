@@ -1,34 +1,690 @@
+// Package config loads the server configuration. Settings come from, in
+// increasing order of precedence, the built-in defaults, the TOML file,
+// AGENTCO_* environment variables and command-line flags. Every setting is
+// addressed by the same dotted key in all of them: http.read_timeout in the
+// file's [http] section is AGENTCO_HTTP_READ_TIMEOUT in the environment and
+// --http.read_timeout on the command line.
 package config
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
-func New(fileName string) *viper.Viper {
-	config := viper.New()
+const envPrefix = "AGENTCO"
 
-	config.SetConfigName(fileName)
+// minTokenSecretLength is the shortest auth.token_secret accepted, the size
+// of the HS256 key.
+const minTokenSecretLength = 32
 
-	config.AddConfigPath(".")
-	config.AddConfigPath("$HOME")
+// redacted replaces secrets when the configuration is printed.
+const redacted = "[REDACTED]"
 
-	err := config.ReadInConfig()
-	if err != nil {
-		log.Fatal("Error while parsing configuration file", err)
+type Config struct {
+	Database   Database   `mapstructure:"database"`
+	HTTP       HTTP       `mapstructure:"http"`
+	HTTPS      HTTPS      `mapstructure:"https"`
+	Health     Health     `mapstructure:"health"`
+	Metrics    Metrics    `mapstructure:"metrics"`
+	Pagination Pagination `mapstructure:"pagination"`
+	Scheduling Scheduling `mapstructure:"scheduling"`
+	Series     Series     `mapstructure:"series"`
+	Auth       Auth       `mapstructure:"auth"`
+	Password   Password   `mapstructure:"password"`
+	Logging    Logging    `mapstructure:"logging"`
+	Tracing    Tracing    `mapstructure:"tracing"`
+	RateLimit  RateLimit  `mapstructure:"rate_limit"`
+	CORS       CORS       `mapstructure:"cors"`
+}
+
+type Database struct {
+	// ConnectionString may carry credentials; its password is redacted
+	// when printed.
+	ConnectionString string        `mapstructure:"connection_string"`
+	DatabaseName     string        `mapstructure:"database_name"`
+	ConnectTimeout   time.Duration `mapstructure:"connect_timeout"`
+}
+
+type HTTP struct {
+	Addr              string        `mapstructure:"http_addr"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
+	ValidateResponses bool          `mapstructure:"validate_responses"`
+	// PublicURL is the URL clients reach the API at, used for the links
+	// handed out in responses. When empty, it is taken from each request.
+	PublicURL string `mapstructure:"public_url"`
+}
+
+type HTTPS struct {
+	Addr            string   `mapstructure:"https_addr"`
+	CertFile        string   `mapstructure:"cert_file"`
+	KeyFile         string   `mapstructure:"key_file"`
+	SelfSigned      bool     `mapstructure:"self_signed"`
+	SelfSignedHosts []string `mapstructure:"self_signed_hosts"`
+	RedirectHTTP    bool     `mapstructure:"redirect_http"`
+}
+
+// Enabled reports whether HTTPS is to be served.
+func (h HTTPS) Enabled() bool {
+	return h.SelfSigned || h.CertFile != "" || h.KeyFile != ""
+}
+
+type Health struct {
+	// CheckTimeout bounds each readiness check.
+	CheckTimeout time.Duration `mapstructure:"check_timeout"`
+	// DrainDelay is how long readiness reports the service as unavailable
+	// on shutdown before the listeners close.
+	DrainDelay time.Duration `mapstructure:"drain_delay"`
+}
+
+type Metrics struct {
+	// Enabled serves Prometheus metrics on /metrics at Addr.
+	Enabled bool `mapstructure:"enabled"`
+	// Addr is a listener of its own, since /metrics is unauthenticated and
+	// never served by the public ones.
+	Addr string `mapstructure:"metrics_addr"`
+}
+
+type Pagination struct {
+	DefaultLimit int `mapstructure:"default_limit"`
+	MaxLimit     int `mapstructure:"max_limit"`
+}
+
+type Scheduling struct {
+	// Buffer is the least time a sitter must have between the end of one
+	// job and the start of the next.
+	Buffer time.Duration `mapstructure:"buffer"`
+}
+
+type Series struct {
+	// Horizon is how far ahead the jobs of a recurring series are created.
+	Horizon time.Duration `mapstructure:"horizon"`
+	// MaterializeInterval is how often the horizon is rolled forward.
+	MaterializeInterval time.Duration `mapstructure:"materialize_interval"`
+}
+
+type Auth struct {
+	TokenSecret string        `mapstructure:"token_secret"`
+	SessionTTL  time.Duration `mapstructure:"session_ttl"`
+}
+
+type Password struct {
+	Argon2Memory      uint32 `mapstructure:"argon2_memory"`
+	Argon2Iterations  uint32 `mapstructure:"argon2_iterations"`
+	Argon2Parallelism uint8  `mapstructure:"argon2_parallelism"`
+	Argon2SaltLength  uint32 `mapstructure:"argon2_salt_length"`
+	Argon2KeyLength   uint32 `mapstructure:"argon2_key_length"`
+	MinLength         int    `mapstructure:"min_length"`
+	MaxLength         int    `mapstructure:"max_length"`
+	RequireUpper      bool   `mapstructure:"require_upper"`
+	RequireLower      bool   `mapstructure:"require_lower"`
+	RequireDigit      bool   `mapstructure:"require_digit"`
+	RequireSymbol     bool   `mapstructure:"require_symbol"`
+}
+
+type Logging struct {
+	// Level is one of debug, info, warn or error.
+	Level string `mapstructure:"level"`
+	// Format is text or json.
+	Format string `mapstructure:"format"`
+}
+
+type Tracing struct {
+	// Exporter is none, otlp, stdout or file.
+	Exporter string `mapstructure:"exporter"`
+	// Endpoint is the host:port of the OTLP/HTTP collector.
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure sends OTLP over plain HTTP.
+	Insecure bool `mapstructure:"insecure"`
+	// File is where the file exporter appends spans, one JSON object each.
+	File string `mapstructure:"file"`
+	// SampleRatio is the fraction of new traces recorded; traces started by
+	// a caller follow the caller's decision.
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+type RateLimit struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Default is the limit of operations not listed in Operations, written
+	// as <requests>/<period>, such as 120/1m.
+	Default string `mapstructure:"default"`
+	// Operations maps operationIds to their own limits.
+	Operations map[string]string `mapstructure:"operations"`
+	// PerIP limits every request from one client IP address, counted before
+	// the request is authenticated, so that bad tokens are throttled too.
+	PerIP string `mapstructure:"per_ip"`
+	// TrustedProxies are the CIDR ranges whose X-Forwarded-For headers are
+	// believed when identifying anonymous clients.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// LockoutThreshold is the number of failed logins for one email after
+	// which logins are refused for LockoutBase, doubling with every further
+	// failure up to LockoutMax. Failures are forgotten after LockoutForget.
+	LockoutThreshold int           `mapstructure:"lockout_threshold"`
+	LockoutBase      time.Duration `mapstructure:"lockout_base"`
+	LockoutMax       time.Duration `mapstructure:"lockout_max"`
+	LockoutForget    time.Duration `mapstructure:"lockout_forget"`
+}
+
+type CORS struct {
+	// AllowedOrigins enables CORS for these origins; empty disables it.
+	// "*" allows any origin and https://*.example.com any subdomain of
+	// example.com.
+	AllowedOrigins   []string      `mapstructure:"allowed_origins"`
+	AllowedMethods   []string      `mapstructure:"allowed_methods"`
+	AllowedHeaders   []string      `mapstructure:"allowed_headers"`
+	ExposedHeaders   []string      `mapstructure:"exposed_headers"`
+	AllowCredentials bool          `mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `mapstructure:"max_age"`
+}
+
+// Default returns the settings used for anything the file, the environment
+// and the flags leave out.
+func Default() Config {
+	return Config{
+		Database: Database{
+			DatabaseName:   "agentco",
+			ConnectTimeout: 10 * time.Second,
+		},
+		HTTP: HTTP{
+			Addr:            ":3000",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		HTTPS: HTTPS{
+			Addr:            ":443",
+			SelfSignedHosts: []string{"localhost", "127.0.0.1", "::1"},
+		},
+		Health: Health{
+			CheckTimeout: 2 * time.Second,
+			DrainDelay:   5 * time.Second,
+		},
+		Metrics: Metrics{
+			Enabled: true,
+			Addr:    "127.0.0.1:9090",
+		},
+		Pagination: Pagination{
+			DefaultLimit: 20,
+			MaxLimit:     50,
+		},
+		Scheduling: Scheduling{
+			Buffer: 30 * time.Minute,
+		},
+		Series: Series{
+			Horizon:             30 * 24 * time.Hour,
+			MaterializeInterval: time.Hour,
+		},
+		Auth: Auth{
+			SessionTTL: 24 * time.Hour,
+		},
+		Password: Password{
+			Argon2Memory:      64 * 1024,
+			Argon2Iterations:  3,
+			Argon2Parallelism: 2,
+			Argon2SaltLength:  16,
+			Argon2KeyLength:   32,
+			MinLength:         8,
+		},
+		Logging: Logging{
+			Level:  "info",
+			Format: "text",
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			Endpoint:    "localhost:4318",
+			File:        "traces.json",
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimit{
+			Enabled: true,
+			Default: "120/1m",
+			Operations: map[string]string{
+				"startSession": "10/1m",
+				"post_users":   "5/1m",
+			},
+			PerIP:            "600/1m",
+			TrustedProxies:   []string{"127.0.0.1/32", "::1/128"},
+			LockoutThreshold: 5,
+			LockoutBase:      time.Minute,
+			LockoutMax:       time.Hour,
+			LockoutForget:    24 * time.Hour,
+		},
+		CORS: CORS{
+			AllowedOrigins: []string{},
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "If-Match", "If-None-Match", "X-Request-ID", "traceparent", "tracestate"},
+			ExposedHeaders: []string{
+				"ETag", "Location", "X-Request-ID", "Retry-After",
+				"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+			},
+			MaxAge: 10 * time.Minute,
+		},
 	}
+}
 
-	return config
+// Options are the command-line flags that are not settings themselves.
+type Options struct {
+	// PrintConfig asks for the effective configuration to be printed.
+	PrintConfig bool
 }
 
-func GetConfigFileName() string {
-	env := os.Getenv("ENV")
+// Load builds the configuration from the defaults, the config file, the
+// environment and args, the command-line arguments without the program
+// name, and validates it. The file is the one named by --config, or else
+// agentco.toml (agentco-$ENV.toml when ENV is set) in the working directory
+// or $HOME. A file named by --config must exist; the default one may be
+// missing.
+func Load(args []string) (*Config, Options, error) {
+	var opts Options
+
+	defaults := Default()
+	v := viper.New()
+	flags := pflag.NewFlagSet("agentco", pflag.ContinueOnError)
+	configFile := flags.String("config", "", "path to the configuration file")
+	flags.BoolVar(&opts.PrintConfig, "print-config", false, "print the effective configuration, with secrets redacted, and exit")
+
+	for _, s := range settings(reflect.ValueOf(defaults), "") {
+		v.SetDefault(s.key, s.value.Interface())
+		addFlag(flags, s)
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, opts, err
+	}
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Name != "config" && f.Name != "print-config" {
+			v.BindPFlag(f.Name, f)
+		}
+	})
 
-	if env != "" {
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	if *configFile != "" {
+		v.SetConfigFile(*configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, opts, fmt.Errorf("reading %s: %w", *configFile, err)
+		}
+	} else {
+		v.SetConfigName(fileName())
+		v.SetConfigType("toml")
+		v.AddConfigPath(".")
+		v.AddConfigPath("$HOME")
+		var notFound viper.ConfigFileNotFoundError
+		if err := v.ReadInConfig(); err != nil && !errors.As(err, &notFound) {
+			return nil, opts, fmt.Errorf("reading configuration file: %w", err)
+		}
+	}
+
+	var config Config
+	hooks := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		stringToMapHook,
+	))
+	if err := v.Unmarshal(&config, hooks); err != nil {
+		return nil, opts, fmt.Errorf("decoding configuration: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, opts, err
+	}
+
+	return &config, opts, nil
+}
+
+// stringToMapHook decodes maps given as a string, as environment variables
+// give them, written as key=value pairs separated by commas.
+func stringToMapHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf(map[string]string{}) {
+		return data, nil
+	}
+
+	m := make(map[string]string)
+	for _, pair := range strings.Split(data.(string), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not key=value", pair)
+		}
+		m[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return m, nil
+}
+
+func fileName() string {
+	if env := os.Getenv("ENV"); env != "" {
 		return "agentco-" + env
 	}
 
 	return "agentco"
 }
+
+// ValidationError lists every problem found in a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Validate reports every problem with c at once, as a *ValidationError.
+func (c *Config) Validate() error {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Database.ConnectionString == "" {
+		problem("database.connection_string is required")
+	}
+	if c.Database.DatabaseName == "" {
+		problem("database.database_name is required")
+	}
+	if c.Database.ConnectTimeout <= 0 {
+		problem("database.connect_timeout must be positive")
+	}
+
+	if !validAddr(c.HTTP.Addr) {
+		problem("http.http_addr %q is not a host:port address", c.HTTP.Addr)
+	}
+	if c.HTTP.ReadTimeout < 0 || c.HTTP.WriteTimeout < 0 || c.HTTP.IdleTimeout < 0 {
+		problem("http.read_timeout, write_timeout and idle_timeout must not be negative")
+	}
+	if c.HTTP.ShutdownTimeout <= 0 {
+		problem("http.shutdown_timeout must be positive")
+	}
+	if c.HTTP.PublicURL != "" {
+		if u, err := url.Parse(c.HTTP.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problem("http.public_url %q must be an http or https URL", c.HTTP.PublicURL)
+		}
+	}
+
+	if c.HTTPS.Enabled() && !validAddr(c.HTTPS.Addr) {
+		problem("https.https_addr %q is not a host:port address", c.HTTPS.Addr)
+	}
+	if !c.HTTPS.SelfSigned && (c.HTTPS.CertFile == "") != (c.HTTPS.KeyFile == "") {
+		problem("https.cert_file and https.key_file must be set together")
+	}
+	if c.HTTPS.SelfSigned && len(c.HTTPS.SelfSignedHosts) == 0 {
+		problem("https.self_signed_hosts must name at least one host")
+	}
+	if c.HTTPS.RedirectHTTP && !c.HTTPS.Enabled() {
+		problem("https.redirect_http needs HTTPS to be configured")
+	}
+
+	if c.Metrics.Enabled {
+		switch {
+		case !validAddr(c.Metrics.Addr):
+			problem("metrics.metrics_addr %q is not a host:port address", c.Metrics.Addr)
+		case c.Metrics.Addr == c.HTTP.Addr || c.HTTPS.Enabled() && c.Metrics.Addr == c.HTTPS.Addr:
+			problem("metrics.metrics_addr %q must not be a public listener", c.Metrics.Addr)
+		}
+	}
+
+	if c.Health.CheckTimeout <= 0 {
+		problem("health.check_timeout must be positive")
+	}
+	if c.Health.DrainDelay < 0 {
+		problem("health.drain_delay must not be negative")
+	}
+
+	if c.Pagination.DefaultLimit < 1 {
+		problem("pagination.default_limit must be at least 1")
+	}
+	if c.Pagination.MaxLimit < 0 {
+		problem("pagination.max_limit must not be negative")
+	}
+	if c.Pagination.MaxLimit > 0 && c.Pagination.DefaultLimit > c.Pagination.MaxLimit {
+		problem("pagination.default_limit must not exceed pagination.max_limit")
+	}
+
+	if c.Scheduling.Buffer < 0 {
+		problem("scheduling.buffer must not be negative")
+	}
+
+	if c.Series.Horizon <= 0 {
+		problem("series.horizon must be positive")
+	}
+	if c.Series.MaterializeInterval <= 0 {
+		problem("series.materialize_interval must be positive")
+	}
+
+	switch {
+	case c.Auth.TokenSecret == "":
+		problem("auth.token_secret is required")
+	case len(c.Auth.TokenSecret) < minTokenSecretLength:
+		problem("auth.token_secret must be at least %d bytes", minTokenSecretLength)
+	}
+	if c.Auth.SessionTTL <= 0 {
+		problem("auth.session_ttl must be positive")
+	}
+
+	p := c.Password
+	if p.Argon2Memory == 0 || p.Argon2Iterations == 0 || p.Argon2Parallelism == 0 {
+		problem("password.argon2_memory, argon2_iterations and argon2_parallelism must be positive")
+	}
+	if p.Argon2SaltLength < 8 {
+		problem("password.argon2_salt_length must be at least 8")
+	}
+	if p.Argon2KeyLength < 16 {
+		problem("password.argon2_key_length must be at least 16")
+	}
+	if p.MinLength < 1 {
+		problem("password.min_length must be at least 1")
+	}
+	if p.MaxLength != 0 && p.MaxLength < p.MinLength {
+		problem("password.max_length must not be less than password.min_length")
+	}
+
+	switch c.Logging.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		problem("logging.level %q must be debug, info, warn or error", c.Logging.Level)
+	}
+	switch c.Logging.Format {
+	case "text", "json":
+	default:
+		problem("logging.format %q must be text or json", c.Logging.Format)
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.Tracing.Endpoint == "" {
+			problem("tracing.endpoint is required by the otlp exporter")
+		}
+	case "file":
+		if c.Tracing.File == "" {
+			problem("tracing.file is required by the file exporter")
+		}
+	default:
+		problem("tracing.exporter %q must be none, otlp, stdout or file", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problem("tracing.sample_ratio must be between 0 and 1")
+	}
+
+	if c.RateLimit.Enabled {
+		if !validLimit(c.RateLimit.Default) {
+			problem("rate_limit.default %q is not <requests>/<period>", c.RateLimit.Default)
+		}
+		if !validLimit(c.RateLimit.PerIP) {
+			problem("rate_limit.per_ip %q is not <requests>/<period>", c.RateLimit.PerIP)
+		}
+		for _, operation := range sortedKeys(c.RateLimit.Operations) {
+			if limit := c.RateLimit.Operations[operation]; !validLimit(limit) {
+				problem("rate_limit.operations.%s %q is not <requests>/<period>", operation, limit)
+			}
+		}
+	}
+	for _, proxy := range c.RateLimit.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			problem("rate_limit.trusted_proxies %q is not a CIDR range", proxy)
+		}
+	}
+	if c.RateLimit.LockoutThreshold < 1 {
+		problem("rate_limit.lockout_threshold must be at least 1")
+	}
+	if c.RateLimit.LockoutBase <= 0 || c.RateLimit.LockoutMax < c.RateLimit.LockoutBase {
+		problem("rate_limit.lockout_base must be positive and not exceed rate_limit.lockout_max")
+	}
+	if c.RateLimit.LockoutForget <= 0 {
+		problem("rate_limit.lockout_forget must be positive")
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			if c.CORS.AllowCredentials {
+				problem("cors.allowed_origins cannot be \"*\" when cors.allow_credentials is set")
+			}
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			problem("cors.allowed_origins %q must be a scheme://host[:port] origin or \"*\"", origin)
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		problem("cors.max_age must not be negative")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// validLimit accepts limits written as <requests>/<period>, such as 10/1m.
+func validLimit(limit string) bool {
+	requests, period, ok := strings.Cut(limit, "/")
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 1 {
+		return false
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	return err == nil && d > 0
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func validAddr(addr string) bool {
+	_, _, err := net.SplitHostPort(addr)
+	return err == nil
+}
+
+// Redacted returns a copy of c that is safe to print or log.
+func (c Config) Redacted() Config {
+	c.Database.ConnectionString = redactURI(c.Database.ConnectionString)
+	if c.Auth.TokenSecret != "" {
+		c.Auth.TokenSecret = redacted
+	}
+
+	return c
+}
+
+// redactURI hides the password of a connection string, keeping the rest
+// readable.
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return redacted
+	}
+	if _, ok := u.User.Password(); !ok {
+		return uri
+	}
+
+	hidden := url.User(u.User.Username()).String() + ":" + redacted
+	return strings.Replace(uri, u.User.String()+"@", hidden+"@", 1)
+}
+
+// String lists every setting as key = value, with secrets redacted.
+func (c Config) String() string {
+	var b strings.Builder
+	for _, s := range settings(reflect.ValueOf(c.Redacted()), "") {
+		fmt.Fprintf(&b, "%s = %v\n", s.key, s.value.Interface())
+	}
+
+	return b.String()
+}
+
+// setting is one leaf of Config, addressed by its dotted key.
+type setting struct {
+	key   string
+	value reflect.Value
+	usage string
+}
+
+// settings walks the struct v and returns its leaves in declaration order.
+func settings(v reflect.Value, prefix string) []setting {
+	var out []setting
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key := prefix + field.Tag.Get("mapstructure")
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)) {
+			out = append(out, settings(v.Field(i), key+".")...)
+			continue
+		}
+		out = append(out, setting{key: key, value: v.Field(i), usage: "overrides " + key})
+	}
+
+	return out
+}
+
+// addFlag registers a --key flag typed after the setting.
+func addFlag(flags *pflag.FlagSet, s setting) {
+	switch value := s.value.Interface().(type) {
+	case string:
+		flags.String(s.key, value, s.usage)
+	case bool:
+		flags.Bool(s.key, value, s.usage)
+	case int:
+		flags.Int(s.key, value, s.usage)
+	case uint8:
+		flags.Uint8(s.key, value, s.usage)
+	case uint32:
+		flags.Uint32(s.key, value, s.usage)
+	case float64:
+		flags.Float64(s.key, value, s.usage)
+	case time.Duration:
+		flags.Duration(s.key, value, s.usage)
+	case []string:
+		flags.StringSlice(s.key, value, s.usage)
+	case map[string]string:
+		flags.StringToString(s.key, value, s.usage)
+	default:
+		panic(fmt.Sprintf("config: no flag type for %s (%T)", s.key, value))
+	}
+}
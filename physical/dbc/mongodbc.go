@@ -2,36 +2,78 @@ package dbc
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
-	"github.com/spf13/viper"
+	"github.com/bersennaidoo/agentco/physical/config"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-func New(config *viper.Viper) *mongo.Client {
-	connectionString := config.GetString("database.connection_string")
+// New connects to MongoDB and checks that the primary is reachable. Every
+// command the client sends is reported to each of monitors. The caller owns
+// the returned client and must Disconnect it on shutdown.
+func New(config config.Database, monitors ...*event.CommandMonitor) (*mongo.Client, error) {
+	if config.ConnectionString == "" {
+		return nil, errors.New("database connection string is missing")
+	}
 
-	if connectionString == "" {
-		log.Fatalf("Database connection string is missing")
+	timeout := config.ConnectTimeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(connectionString))
-	if err = client.Ping(context.TODO(), readpref.Primary()); err != nil {
-		log.Fatal(err)
+	clientOptions := options.Client().ApplyURI(config.ConnectionString)
+	if len(monitors) > 0 {
+		clientOptions.SetMonitor(combineMonitors(monitors))
+	}
+
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to MongoDB: %w", err)
+	}
+
+	if err = client.Ping(ctx, readpref.Primary()); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("MongoDB is unreachable: %w", err)
 	}
 
-	log.Println("Connected to MongoDB")
-	defer func() {
-		if err = client.Disconnect(ctx); err != nil {
-			panic(err)
-		}
-	}()
+	slog.Info("Connected to MongoDB")
+
+	return client, nil
+}
 
-	return client
+// combineMonitors returns a monitor passing every event on to each of
+// monitors, since the driver accepts only one.
+func combineMonitors(monitors []*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				if m.Started != nil {
+					m.Started(ctx, e)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				if m.Succeeded != nil {
+					m.Succeeded(ctx, e)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				if m.Failed != nil {
+					m.Failed(ctx, e)
+				}
+			}
+		},
+	}
 }
@@ -0,0 +1,81 @@
+// Package logging sets up the structured logger and carries a request-scoped
+// logger through the context, so that everything logged while serving a
+// request shares its request ID and, once known, its user ID.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/bersennaidoo/agentco/physical/config"
+)
+
+// New returns a logger writing to w in the configured format, dropping
+// records below the configured level.
+func New(config config.Logging, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.Level)); err != nil {
+		return nil, fmt.Errorf("logging.level: %w", err)
+	}
+	options := &slog.HandlerOptions{Level: level}
+
+	switch config.Format {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("logging.format %q must be text or json", config.Format)
+	}
+}
+
+type contextKey struct{}
+
+// scope holds a request's logger. It is shared by every context derived from
+// the request's, so attributes added deep in the handler chain show up in
+// the access log written by the outermost middleware.
+type scope struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+}
+
+// With returns a copy of ctx carrying logger.
+func With(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, &scope{logger: logger})
+}
+
+// FromContext returns the logger carried by ctx, or slog.Default() when
+// there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	s, ok := ctx.Value(contextKey{}).(*scope)
+	if !ok {
+		return slog.Default()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.logger
+}
+
+// AddAttrs adds attrs to the logger carried by ctx, for everyone logging
+// through ctx or a context derived from the same request. It does nothing
+// when ctx carries no logger.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	s, ok := ctx.Value(contextKey{}).(*scope)
+	if !ok {
+		return
+	}
+
+	args := make([]any, len(attrs))
+	for i, attr := range attrs {
+		args[i] = attr
+	}
+
+	s.mu.Lock()
+	s.logger = s.logger.With(args...)
+	s.mu.Unlock()
+}